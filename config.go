@@ -0,0 +1,347 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Config is the declarative form of the generator options, for settings shared
+// between programs, a CLI and teammates who do not write Go. It is usually read
+// from a JSON document with NewFromConfig or LoadConfig:
+//
+//	{
+//	  "version": "draft-07",
+//	  "indent": "  ",
+//	  "examples": true,
+//	  "maxSamples": 100000,
+//	  "formats": {"order-id": "^ORD-[0-9]{8}$"},
+//	  "predefined": {"created_at": "datetime", "items[].sku": "string"},
+//	  "overrides": [{"path": "status", "schema": {"enum": ["on", "off"]}, "merge": true}],
+//	  "ignore": ["debug", "**._metadata"],
+//	  "sampling": {"strategy": "reservoir", "size": 10000}
+//	}
+//
+// Only JSON is read by LoadConfig; a Config filled in by other means is turned
+// into options with Config.Options.
+type Config struct {
+	// Version is the JSON Schema draft: "draft-06", "draft-07", "2019-09",
+	// "2020-12" or the full $schema URI.
+	Version string `json:"version,omitempty"`
+	// Indent is the indentation of the generated JSON; empty means compact.
+	Indent string `json:"indent,omitempty"`
+	// Examples enables example capturing (WithExamples).
+	Examples bool `json:"examples,omitempty"`
+	// ExampleCount emits up to this many examples per field (WithExampleCount(n)).
+	ExampleCount int `json:"exampleCount,omitempty"`
+	// Stats records value statistics (WithStats).
+	Stats bool `json:"stats,omitempty"`
+	// StatsAnnotations writes statistics into the schema (WithStatsAnnotations).
+	StatsAnnotations bool `json:"statsAnnotations,omitempty"`
+	// Redact redacts sensitive examples (WithRedaction).
+	Redact bool `json:"redact,omitempty"`
+	// RootExamples emits up to this many whole samples at the root (WithRootExamples).
+	RootExamples int `json:"rootExamples,omitempty"`
+	// MaxSamples caps the number of samples processed (WithMaxSamples).
+	MaxSamples int `json:"maxSamples,omitempty"`
+	// BuiltInFormats can be set to false to disable built-in format detectors.
+	BuiltInFormats *bool `json:"builtInFormats,omitempty"`
+	// Formats maps custom format names to the regular expression their values
+	// match (WithPatternFormat).
+	Formats map[string]string `json:"formats,omitempty"`
+	// FormatTolerance is the fraction of strings a format must match (WithFormatTolerance).
+	FormatTolerance float64 `json:"formatTolerance,omitempty"`
+	// FormatCandidates lists all matching formats (WithFormatCandidates).
+	FormatCandidates bool `json:"formatCandidates,omitempty"`
+	// Timestamps enables non-standard timestamp recognition (WithTimestampDetection).
+	Timestamps bool `json:"timestamps,omitempty"`
+	// EmbeddedJSON enables inference of JSON encoded in strings (WithEmbeddedJSON).
+	EmbeddedJSON bool `json:"embeddedJSON,omitempty"`
+	// EncodedContent enables encoding detection (WithEncodedContent).
+	EncodedContent bool `json:"encodedContent,omitempty"`
+	// JWTClaims enables the inference of JWT claims (WithJWTClaims).
+	JWTClaims bool `json:"jwtClaims,omitempty"`
+	// PatternInference is the minimum number of strings before an inferred
+	// pattern is emitted (WithPatternInference); 0 disables pattern inference.
+	PatternInference int `json:"patternInference,omitempty"`
+	// DefaultInference is the share of values the most frequent value needs
+	// to be emitted as default (WithDefaultInference); 0 disables defaults.
+	DefaultInference float64 `json:"defaultInference,omitempty"`
+	// NumericPrecision emits multipleOf and x-precision (WithNumericPrecision).
+	NumericPrecision bool `json:"numericPrecision,omitempty"`
+	// Predefined maps paths to predefined types (WithPredefinedPath).
+	Predefined map[string]PredefinedType `json:"predefined,omitempty"`
+	// Overrides lists sub-schema overrides (WithOverride / WithMergedOverride).
+	Overrides []OverrideConfig `json:"overrides,omitempty"`
+	// Ignore lists path patterns excluded from inference (WithIgnorePaths).
+	Ignore []string `json:"ignore,omitempty"`
+	// IgnorePlaceholders keeps ignored fields as {} placeholders (WithIgnoredPlaceholders).
+	IgnorePlaceholders bool `json:"ignorePlaceholders,omitempty"`
+	// Sampling selects a sampling strategy (WithSampling).
+	Sampling *SamplingConfig `json:"sampling,omitempty"`
+}
+
+// OverrideConfig is the declarative form of WithOverride and WithMergedOverride.
+type OverrideConfig struct {
+	Path   string  `json:"path"`
+	Schema *Schema `json:"schema"`
+	Merge  bool    `json:"merge,omitempty"`
+}
+
+// SamplingConfig is the declarative form of WithSampling.
+type SamplingConfig struct {
+	// Strategy is one of "reservoir", "every-kth", "time-bucketed" or "structure-only".
+	Strategy string `json:"strategy"`
+	// Size is the reservoir size, the per-bucket cap or the value-tracking limit.
+	Size int `json:"size,omitempty"`
+	// Every is the K of "every-kth".
+	Every int `json:"every,omitempty"`
+	// Width is the bucket width of "time-bucketed", as a Go duration ("24h").
+	Width string `json:"width,omitempty"`
+	// TimestampPath is the path of the RFC 3339 timestamp used by "time-bucketed".
+	TimestampPath string `json:"timestampPath,omitempty"`
+	// Seed seeds randomised strategies (WithSamplingSeed).
+	Seed uint64 `json:"seed,omitempty"`
+}
+
+// LoadConfig reads a JSON configuration document from r. Unknown keys are
+// rejected so that typos do not go unnoticed.
+func LoadConfig(r io.Reader) (*Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// NewFromConfig reads a JSON configuration document from r and creates a
+// Generator from it. Extra options are applied after the configuration ones.
+func NewFromConfig(r io.Reader, opts ...Option) (*Generator, error) {
+	cfg, err := LoadConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	cfgOpts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	return New(append(cfgOpts, opts...)...), nil
+}
+
+// Options validates the configuration and converts it to generator options,
+// for use with New or NewParallel.
+func (c *Config) Options() ([]Option, error) {
+	var opts []Option
+
+	if c.Version != "" {
+		version, err := parseSchemaVersion(c.Version)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithSchemaVersion(version))
+	}
+	if c.Indent != "" {
+		opts = append(opts, WithIndent(c.Indent))
+	}
+	if c.Examples {
+		opts = append(opts, WithExamples())
+	}
+	if c.ExampleCount > 0 {
+		opts = append(opts, WithExampleCount(c.ExampleCount))
+	}
+	if c.RootExamples > 0 {
+		opts = append(opts, WithRootExamples(c.RootExamples))
+	}
+	if c.Redact {
+		opts = append(opts, WithRedaction())
+	}
+	if c.Stats {
+		opts = append(opts, WithStats())
+	}
+	if c.StatsAnnotations {
+		opts = append(opts, WithStatsAnnotations())
+	}
+	if c.MaxSamples > 0 {
+		opts = append(opts, WithMaxSamples(c.MaxSamples))
+	}
+
+	if c.BuiltInFormats != nil && !*c.BuiltInFormats {
+		opts = append(opts, WithoutBuiltInFormats())
+	}
+	// Register formats in name order so that the configuration is deterministic.
+	names := make([]string, 0, len(c.Formats))
+	for name := range c.Formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(c.Formats[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for format %q: %w", name, err)
+		}
+		opts = append(opts, withPatternFormat(name, re))
+	}
+	if c.FormatTolerance != 0 {
+		if c.FormatTolerance < 0 || c.FormatTolerance > 1 {
+			return nil, fmt.Errorf("invalid format tolerance %v: must be between 0 and 1", c.FormatTolerance)
+		}
+		opts = append(opts, WithFormatTolerance(c.FormatTolerance))
+	}
+	if c.FormatCandidates {
+		opts = append(opts, WithFormatCandidates())
+	}
+	if c.Timestamps {
+		opts = append(opts, WithTimestampDetection())
+	}
+	if c.EmbeddedJSON {
+		opts = append(opts, WithEmbeddedJSON())
+	}
+	if c.EncodedContent {
+		opts = append(opts, WithEncodedContent())
+	}
+	if c.JWTClaims {
+		opts = append(opts, WithJWTClaims())
+	}
+	if c.PatternInference > 0 {
+		opts = append(opts, WithPatternInference(c.PatternInference))
+	}
+	if c.NumericPrecision {
+		opts = append(opts, WithNumericPrecision())
+	}
+	if c.DefaultInference != 0 {
+		if c.DefaultInference < 0 || c.DefaultInference > 1 {
+			return nil, fmt.Errorf("invalid default inference ratio %v: must be between 0 and 1", c.DefaultInference)
+		}
+		opts = append(opts, WithDefaultInference(c.DefaultInference))
+	}
+
+	paths := make([]string, 0, len(c.Predefined))
+	for path := range c.Predefined {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		typ := c.Predefined[path]
+		if !typ.valid() {
+			return nil, fmt.Errorf("invalid predefined type %q for %q", typ, path)
+		}
+		opts = append(opts, WithPredefinedPath(path, typ))
+	}
+
+	for i, o := range c.Overrides {
+		if o.Schema == nil {
+			return nil, fmt.Errorf("override %d (%q) has no schema", i, o.Path)
+		}
+		if o.Merge {
+			opts = append(opts, WithMergedOverride(o.Path, o.Schema))
+		} else {
+			opts = append(opts, WithOverride(o.Path, o.Schema))
+		}
+	}
+
+	if len(c.Ignore) > 0 {
+		opts = append(opts, WithIgnorePaths(c.Ignore...))
+	}
+	if c.IgnorePlaceholders {
+		opts = append(opts, WithIgnoredPlaceholders())
+	}
+
+	if c.Sampling != nil {
+		strategy, err := c.Sampling.strategy()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithSampling(strategy))
+		if c.Sampling.Seed != 0 {
+			opts = append(opts, WithSamplingSeed(c.Sampling.Seed))
+		}
+	}
+
+	return opts, nil
+}
+
+// strategy converts the sampling configuration to a SamplingStrategy.
+func (c *SamplingConfig) strategy() (SamplingStrategy, error) {
+	switch c.Strategy {
+	case "reservoir", "time-bucketed", "structure-only":
+		if c.Size <= 0 {
+			return SamplingStrategy{}, fmt.Errorf("invalid sampling size %d for %q: must be positive", c.Size, c.Strategy)
+		}
+	}
+
+	switch c.Strategy {
+	case "reservoir":
+		return Reservoir(c.Size), nil
+	case "every-kth":
+		return EveryKth(c.Every), nil
+	case "structure-only":
+		return StructureOnlyAfter(c.Size), nil
+	case "time-bucketed":
+		width, err := time.ParseDuration(c.Width)
+		if err != nil || width <= 0 {
+			return SamplingStrategy{}, fmt.Errorf("invalid sampling width %q", c.Width)
+		}
+		if c.TimestampPath == "" {
+			return SamplingStrategy{}, fmt.Errorf("time-bucketed sampling requires timestampPath")
+		}
+		pattern := parsePath(c.TimestampPath)
+		timestamp := func(sample interface{}) (time.Time, bool) {
+			s, ok := lookupPath(sample, pattern).(string)
+			if !ok {
+				return time.Time{}, false
+			}
+			ts, err := time.Parse(time.RFC3339Nano, s)
+			return ts, err == nil
+		}
+		return TimeBucketed(width, c.Size, timestamp), nil
+	}
+	return SamplingStrategy{}, fmt.Errorf("unknown sampling strategy %q", c.Strategy)
+}
+
+// schemaVersionNames are the draft names accepted in place of a $schema URI.
+var schemaVersionNames = map[string]SchemaVersion{
+	"draft-06": Draft06,
+	"draft-07": Draft07,
+	"2019-09":  Draft201909,
+	"2020-12":  Draft202012,
+}
+
+// parseSchemaVersion accepts a draft name such as "draft-07" or a $schema URI.
+func parseSchemaVersion(s string) (SchemaVersion, error) {
+	if version, ok := schemaVersionNames[s]; ok {
+		return version, nil
+	}
+	for _, version := range schemaVersionNames {
+		if s == string(version) {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported schema version %q", s)
+}
+
+// valid reports whether t is one of the predefined type constants.
+func (t PredefinedType) valid() bool {
+	switch t {
+	case DateTime, String, Boolean, Number, Integer, Array, Object:
+		return true
+	}
+	return false
+}
+
+// lookupPath returns the value at pattern inside value, or nil if there is none.
+// Only plain property segments are followed; wildcards and array items never match.
+func lookupPath(value interface{}, pattern pathPattern) interface{} {
+	for _, segment := range pattern {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = obj[segment]
+	}
+	return value
+}
@@ -0,0 +1,161 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	config := `{
+		"version": "draft-06",
+		"indent": "  ",
+		"examples": true,
+		"formats": {"order-id": "^ORD-[0-9]{8}$"},
+		"predefined": {"user.zip": "string"},
+		"overrides": [
+			{"path": "status", "schema": {"enum": ["on", "off"]}, "merge": true},
+			{"path": "/meta", "schema": {"$ref": "#/definitions/meta"}}
+		],
+		"ignore": ["**.debug"]
+	}`
+
+	generator, err := NewFromConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("Failed to create generator from config: %v", err)
+	}
+	generator.AddSample(`{"order": "ORD-12345678", "user": {"zip": 75001, "debug": 1}, "status": "on", "meta": {"a": 1}}`)
+
+	schemaJSON, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if !strings.Contains(schemaJSON, "\n  ") {
+		t.Error("Expected indented output")
+	}
+
+	schema := generator.GetCurrentSchema()
+	if schema.Schema != string(Draft06) {
+		t.Errorf("Expected Draft06, got %s", schema.Schema)
+	}
+	if schema.Properties["order"].Format != "order-id" {
+		t.Errorf("Expected order-id format, got %q", schema.Properties["order"].Format)
+	}
+	if schema.Properties["order"].Example != "ORD-12345678" {
+		t.Errorf("Expected example, got %v", schema.Properties["order"].Example)
+	}
+	user := schema.Properties["user"]
+	if user.Properties["zip"].Type != "string" {
+		t.Errorf("Expected zip to be string, got %v", user.Properties["zip"].Type)
+	}
+	if user.Properties["debug"] != nil {
+		t.Error("Expected debug to be ignored")
+	}
+	if status := schema.Properties["status"]; len(status.Enum) != 2 || status.Type != "string" {
+		t.Errorf("Expected merged enum on status, got %+v", status)
+	}
+	if schema.Properties["meta"].Ref != "#/definitions/meta" {
+		t.Errorf("Expected meta $ref, got %+v", schema.Properties["meta"])
+	}
+}
+
+func TestNewFromConfigSampling(t *testing.T) {
+	config := `{
+		"maxSamples": 100,
+		"sampling": {"strategy": "time-bucketed", "width": "1h", "size": 1, "timestampPath": "meta.ts"}
+	}`
+	generator, err := NewFromConfig(strings.NewReader(config))
+	if err != nil {
+		t.Fatalf("Failed to create generator from config: %v", err)
+	}
+	generator.AddSample(`{"meta": {"ts": "2024-01-01T10:00:00Z"}, "a": 1}`)
+	generator.AddSample(`{"meta": {"ts": "2024-01-01T10:30:00Z"}, "b": 1}`)
+	generator.AddSample(`{"meta": {"ts": "2024-01-01T11:00:00Z"}, "c": 1}`)
+
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["a"] == nil || schema.Properties["b"] != nil || schema.Properties["c"] == nil {
+		t.Errorf("Expected one sample per hour, got properties %v", schema.Properties)
+	}
+}
+
+func TestNewFromConfigBuiltInFormats(t *testing.T) {
+	generator, err := NewFromConfig(strings.NewReader(`{"builtInFormats": false}`))
+	if err != nil {
+		t.Fatalf("Failed to create generator from config: %v", err)
+	}
+	generator.AddSample(`{"email": "a@example.com"}`)
+	if format := generator.GetCurrentSchema().Properties["email"].Format; format != "" {
+		t.Errorf("Expected no format, got %q", format)
+	}
+}
+
+func TestNewFromConfigErrors(t *testing.T) {
+	tests := map[string]string{
+		"syntax":         `{"version": }`,
+		"unknown key":    `{"indnet": "  "}`,
+		"version":        `{"version": "draft-99"}`,
+		"version prefix": `{"version": "draft"}`,
+		"version part":   `{"version": "schema"}`,
+		"format regex":   `{"formats": {"bad": "[a-"}}`,
+		"predefined":     `{"predefined": {"a": "uuid"}}`,
+		"override":       `{"overrides": [{"path": "a"}]}`,
+		"tolerance":      `{"formatTolerance": 1.5}`,
+		"default ratio":  `{"defaultInference": -0.5}`,
+		"sampling":       `{"sampling": {"strategy": "random"}}`,
+		"width":          `{"sampling": {"strategy": "time-bucketed", "width": "soon", "timestampPath": "ts"}}`,
+		"reservoir size": `{"sampling": {"strategy": "reservoir"}}`,
+		"bucket size":    `{"sampling": {"strategy": "time-bucketed", "width": "1h", "size": 0, "timestampPath": "ts"}}`,
+		"structure size": `{"sampling": {"strategy": "structure-only", "size": -1}}`,
+	}
+	for name, config := range tests {
+		if _, err := NewFromConfig(strings.NewReader(config)); err == nil {
+			t.Errorf("%s: expected an error for %s", name, config)
+		}
+	}
+}
+
+func TestConfigOptionsWithParallel(t *testing.T) {
+	cfg := &Config{Version: string(Draft06), Ignore: []string{"x"}}
+	opts, err := cfg.Options()
+	if err != nil {
+		t.Fatalf("Failed to convert config: %v", err)
+	}
+
+	parallel := NewParallel(2, opts...)
+	parallel.AddSample(`{"x": 1, "y": 2}`)
+	schema := parallel.GetCurrentSchema()
+	if schema.Schema != string(Draft06) || schema.Properties["x"] != nil {
+		t.Errorf("Expected config to apply to parallel generator, got %+v", schema)
+	}
+}
+
+func TestNewFromConfigEmbeddedJSON(t *testing.T) {
+	generator, err := NewFromConfig(strings.NewReader(`{"version": "2020-12", "embeddedJSON": true}`))
+	if err != nil {
+		t.Fatalf("Failed to create generator from config: %v", err)
+	}
+	generator.AddSample(`{"payload": "{\"a\": 1}"}`)
+
+	schema := generator.GetCurrentSchema()
+	if schema.Schema != string(Draft202012) {
+		t.Errorf("Expected Draft202012, got %s", schema.Schema)
+	}
+	if schema.Properties["payload"].ContentSchema == nil {
+		t.Errorf("Expected embedded JSON content schema, got %+v", schema.Properties["payload"])
+	}
+}
+
+func TestParseSchemaVersion(t *testing.T) {
+	tests := map[string]SchemaVersion{
+		"draft-06":          Draft06,
+		"draft-07":          Draft07,
+		"2019-09":           Draft201909,
+		"2020-12":           Draft202012,
+		string(Draft07):     Draft07,
+		string(Draft202012): Draft202012,
+	}
+	for name, want := range tests {
+		if got, err := parseSchemaVersion(name); err != nil || got != want {
+			t.Errorf("%q: expected %s, got %s (%v)", name, want, got, err)
+		}
+	}
+}
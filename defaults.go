@@ -0,0 +1,188 @@
+package jsonschema
+
+import "sort"
+
+// maxTrackedValues bounds the number of distinct values counted per node by
+// WithDefaultInference.
+const maxTrackedValues = 16
+
+// valueCount is a space-saving counter entry: count may overestimate the
+// occurrences of a value by up to err, when it took over an evicted entry.
+type valueCount struct {
+	count, err int
+}
+
+// valueCounter keeps approximate counts of the most frequent values of a node
+// using the space-saving algorithm: once maxTrackedValues values are tracked,
+// a new value replaces the least frequent one and inherits its count as error.
+type valueCounter struct {
+	counts map[any]valueCount
+	total  int
+}
+
+// add counts one occurrence of value.
+func (c *valueCounter) add(value any) {
+	c.total++
+	if c.counts == nil {
+		c.counts = make(map[any]valueCount)
+	}
+	if vc, ok := c.counts[value]; ok {
+		vc.count++
+		c.counts[value] = vc
+		return
+	}
+	if len(c.counts) < maxTrackedValues {
+		c.counts[value] = valueCount{count: 1}
+		return
+	}
+	evicted, least := c.least()
+	delete(c.counts, evicted)
+	c.counts[value] = valueCount{count: least.count + 1, err: least.count}
+}
+
+// least returns the tracked value with the lowest count.
+func (c *valueCounter) least() (any, valueCount) {
+	var value any
+	var least valueCount
+	first := true
+	for v, vc := range c.counts {
+		if first || vc.count < least.count {
+			value, least, first = v, vc, false
+		}
+	}
+	return value, least
+}
+
+// merge folds the counts of other into c. A value tracked by only one side
+// may have been evicted by the other, so it gets the smallest count of the
+// other side as error; the least frequent entries are then dropped.
+func (c *valueCounter) merge(other *valueCounter) {
+	if other.total == 0 {
+		return
+	}
+	var ownFloor, otherFloor int
+	if len(c.counts) == maxTrackedValues {
+		_, least := c.least()
+		ownFloor = least.count
+	}
+	if len(other.counts) == maxTrackedValues {
+		_, least := other.least()
+		otherFloor = least.count
+	}
+
+	merged := make(map[any]valueCount, len(c.counts)+len(other.counts))
+	for v, vc := range c.counts {
+		if ovc, ok := other.counts[v]; ok {
+			merged[v] = valueCount{count: vc.count + ovc.count, err: vc.err + ovc.err}
+		} else {
+			merged[v] = valueCount{count: vc.count + otherFloor, err: vc.err + otherFloor}
+		}
+	}
+	for v, ovc := range other.counts {
+		if _, ok := c.counts[v]; !ok {
+			merged[v] = valueCount{count: ovc.count + ownFloor, err: ovc.err + ownFloor}
+		}
+	}
+	for len(merged) > maxTrackedValues {
+		evicted, _ := (&valueCounter{counts: merged}).least()
+		delete(merged, evicted)
+	}
+	c.counts = merged
+	c.total += other.total
+}
+
+// top returns the value with the highest guaranteed count (count - err), and
+// that count. Ties are broken by the estimated count, then by value, so the
+// result does not depend on map order.
+func (c *valueCounter) top() (any, int) {
+	values := make([]any, 0, len(c.counts))
+	for v := range c.counts {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		a, b := c.counts[values[i]], c.counts[values[j]]
+		if ga, gb := a.count-a.err, b.count-b.err; ga != gb {
+			return ga > gb
+		}
+		if a.count != b.count {
+			return a.count > b.count
+		}
+		return lessValue(values[i], values[j])
+	})
+	if len(values) == 0 {
+		return nil, 0
+	}
+	vc := c.counts[values[0]]
+	return values[0], vc.count - vc.err
+}
+
+// lessValue orders primitive JSON values: booleans, then numbers, then strings.
+func lessValue(a, b any) bool {
+	rank := func(v any) int {
+		switch v.(type) {
+		case bool:
+			return 0
+		case float64:
+			return 1
+		}
+		return 2
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra < rb
+	}
+	switch a := a.(type) {
+	case bool:
+		return !a && b.(bool)
+	case float64:
+		return a < b.(float64)
+	case string:
+		s, _ := b.(string)
+		return a < s
+	}
+	return false
+}
+
+// observeDefault counts a primitive value for WithDefaultInference.
+func (n *SchemaNode) observeDefault(value any) {
+	switch value.(type) {
+	case string, float64, bool:
+		n.values.add(value)
+	}
+}
+
+// applyDefault emits the most frequent value as "default" when it accounts for
+// at least ratio of the values of the node. Fields that always hold the same
+// value already get a const.
+func (n *SchemaNode) applyDefault(schema *Schema, opts *renderOptions) {
+	if opts.defaultRatio <= 0 || schema.Const != nil || schema.Default != nil || n.values.total == 0 {
+		return
+	}
+	value, count := n.values.top()
+	if float64(count) >= opts.defaultRatio*float64(n.values.total) {
+		schema.Default = value
+	}
+}
+
+// applyOptionalDefault emits the majority value of an optional field as
+// "default", whatever the ratio, since it is the value a consumer most likely
+// means when the field is left out.
+func (n *SchemaNode) applyOptionalDefault(schema *Schema, opts *renderOptions) {
+	if opts.defaultRatio <= 0 || schema.Const != nil || schema.Default != nil || n.values.total == 0 ||
+		n.override != nil && !n.override.merge {
+		return
+	}
+	if value, count := n.values.top(); 2*count > n.values.total {
+		schema.Default = value
+	}
+}
+
+// loadedDefault restores the counts of a loaded node: the default, if any, is
+// the value of all count values the schema stands for.
+func (n *SchemaNode) loadedDefault(schema *Schema, count int) {
+	switch schema.Default.(type) {
+	case string, float64, bool:
+		n.values = valueCounter{counts: map[any]valueCount{schema.Default: {count: count}}, total: count}
+	default:
+		n.values = valueCounter{total: count}
+	}
+}
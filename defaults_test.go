@@ -0,0 +1,133 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDefaultInference(t *testing.T) {
+	generator := New(WithDefaultInference(0.9))
+	for i := 0; i < 100; i++ {
+		currency := "EUR"
+		if i%33 == 0 {
+			currency = "USD"
+		}
+		generator.AddSample(fmt.Sprintf(`{"currency": %q, "active": %t, "status": %q, "id": %d, "kind": "order"}`,
+			currency, i%20 != 0, []string{"new", "paid", "shipped"}[i%3], i))
+	}
+
+	schema := generator.GetCurrentSchema()
+	if got := schema.Properties["currency"].Default; got != "EUR" {
+		t.Errorf("Expected currency default EUR, got %v", got)
+	}
+	if got := schema.Properties["active"].Default; got != true {
+		t.Errorf("Expected active default true, got %v", got)
+	}
+	for _, field := range []string{"status", "id", "kind"} {
+		if got := schema.Properties[field].Default; got != nil {
+			t.Errorf("%s: expected no default, got %v", field, got)
+		}
+	}
+	if schema.Properties["kind"].Const != "order" {
+		t.Errorf("Expected const for a constant field, got %+v", schema.Properties["kind"])
+	}
+}
+
+func TestDefaultInferenceOptionalMajority(t *testing.T) {
+	generator := New(WithDefaultInference(0.95))
+	generator.AddSample(`{"id": 1, "role": "user", "level": 0}`)
+	generator.AddSample(`{"id": 2, "role": "user", "level": 1}`)
+	generator.AddSample(`{"id": 3, "role": "admin", "level": 2}`)
+	generator.AddSample(`{"id": 4}`)
+
+	schema := generator.GetCurrentSchema()
+	if got := schema.Properties["role"].Default; got != "user" {
+		t.Errorf("Expected optional majority default user, got %v", got)
+	}
+	if got := schema.Properties["level"].Default; got != nil {
+		t.Errorf("Expected no default without a majority, got %v", got)
+	}
+}
+
+func TestDefaultInferenceDisabled(t *testing.T) {
+	generator := New()
+	generator.AddSample(`{"currency": "EUR"}`)
+	generator.AddSample(`{"currency": "EUR"}`)
+	generator.AddSample(`{"currency": "USD"}`)
+	generator.AddSample(`{}`)
+
+	schemaJSON, _ := generator.Generate()
+	if strings.Contains(schemaJSON, `"default"`) {
+		t.Errorf("Expected no default without WithDefaultInference, got %s", schemaJSON)
+	}
+}
+
+func TestDefaultInferenceBoundedCounts(t *testing.T) {
+	generator := New(WithDefaultInference(0.5))
+	for i := 0; i < 1000; i++ {
+		value := "common"
+		if i%2 == 1 {
+			value = fmt.Sprintf("rare-%d", i)
+		}
+		generator.AddSample(fmt.Sprintf(`{"tag": %q}`, value))
+	}
+
+	node := generator.rootNode.objectProperties["tag"]
+	if len(node.values.counts) > maxTrackedValues {
+		t.Errorf("Expected at most %d tracked values, got %d", maxTrackedValues, len(node.values.counts))
+	}
+	if got := generator.GetCurrentSchema().Properties["tag"].Default; got != "common" {
+		t.Errorf("Expected default common despite many distinct values, got %v", got)
+	}
+}
+
+func TestDefaultInferenceLoad(t *testing.T) {
+	generator := New(WithDefaultInference(0.6))
+	generator.AddSample(`{"currency": "EUR"}`)
+	generator.AddSample(`{"currency": "EUR"}`)
+	generator.AddSample(`{"currency": "USD"}`)
+	saved, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if !strings.Contains(saved, `"default":"EUR"`) {
+		t.Fatalf("Expected default in %s", saved)
+	}
+
+	restored := New(WithDefaultInference(0.6))
+	if err := restored.Load(saved); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	restored.AddSample(`{"currency": "EUR"}`)
+	restored.AddSample(`{"currency": "GBP"}`)
+	if got := restored.GetCurrentSchema().Properties["currency"].Default; got != "EUR" {
+		t.Errorf("Expected loaded default to be kept, got %v", got)
+	}
+}
+
+func TestDefaultInferenceParallel(t *testing.T) {
+	sequential := New(WithDefaultInference(0.8))
+	parallel := NewParallel(4, WithDefaultInference(0.8))
+	for i := 0; i < 200; i++ {
+		currency := "EUR"
+		if i%7 == 0 {
+			currency = fmt.Sprintf("X%02d", i%40)
+		}
+		sample := fmt.Sprintf(`{"currency": %q, "count": %d}`, currency, i%3)
+		sequential.AddSample(sample)
+		parallel.AddSample(sample)
+	}
+
+	want, _ := sequential.Generate()
+	got, err := parallel.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if got != want {
+		t.Errorf("Parallel schema differs from sequential\ngot:  %s\nwant: %s", got, want)
+	}
+	if !strings.Contains(got, `"default":"EUR"`) {
+		t.Errorf("Expected EUR default, got %s", got)
+	}
+}
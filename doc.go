@@ -166,6 +166,9 @@
 //	generator.AddSample(`{"name": "John"}`)
 //	// Result: name field will have example: "John"
 //
+// WithExampleCount(n) emits up to n representative values per field as a standard
+// "examples" array instead, and WithRootExamples(n) adds whole samples at the root.
+//
 // # Lazy Schema Building
 //
 // The schema is built on demand when Generate() or GetCurrentSchema() is called, not
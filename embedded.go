@@ -0,0 +1,99 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// embeddedMediaType is the contentMediaType of strings holding JSON.
+const embeddedMediaType = "application/json"
+
+// observeEmbedded parses str as embedded JSON and observes its content in
+// n.embedded. The first string that is not a JSON object or array turns
+// detection off for the node for good.
+func (n *SchemaNode) observeEmbedded(str string, opts *observeOptions) {
+	if n.embeddedOff {
+		return
+	}
+
+	var content interface{}
+	trimmed := strings.TrimSpace(str)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') || json.Unmarshal([]byte(trimmed), &content) != nil {
+		n.embeddedOff = true
+		n.embedded = nil
+		return
+	}
+
+	if n.embedded == nil {
+		n.embedded = NewSchemaNode()
+	}
+	// The content is observed at the path of the string itself, so that path
+	// options address it as if it was not encoded.
+	n.embedded.observe(content, opts)
+}
+
+// mergeEmbedded folds the embedded JSON observed by other into n.
+func (n *SchemaNode) mergeEmbedded(other *SchemaNode) {
+	if n.embeddedOff {
+		return
+	}
+	if other.embeddedOff {
+		n.embeddedOff = true
+		n.embedded = nil
+		return
+	}
+	if other.embedded != nil {
+		if n.embedded == nil {
+			n.embedded = NewSchemaNode()
+		}
+		n.embedded.merge(other.embedded)
+	}
+}
+
+// applyEmbedded describes the embedded JSON of a string node: the media type,
+// and the inferred schema as contentSchema, or as x-contentSchema for drafts
+// older than 2019-09.
+func (n *SchemaNode) applyEmbedded(schema *Schema, opts *renderOptions) {
+	if n.embedded == nil || n.embeddedOff {
+		return
+	}
+
+	schema.ContentMediaType = embeddedMediaType
+	content := n.embedded.toSchema(opts)
+	switch opts.version {
+	case Draft06, Draft07:
+		schema.setExtension("x-contentSchema", content)
+	default:
+		schema.ContentSchema = content
+	}
+}
+
+// loadedEmbedded returns the embedded content schema of a loaded schema, if any.
+func loadedEmbedded(schema *Schema) (*Schema, bool) {
+	if schema.ContentMediaType != embeddedMediaType {
+		return nil, false
+	}
+	if schema.ContentSchema != nil {
+		return schema.ContentSchema, true
+	}
+
+	return extensionSchema(schema, "x-contentSchema")
+}
+
+// extensionSchema returns the schema held by the "x-" keyword key of a loaded
+// schema. Extensions are unmarshalled as generic values, so it is decoded again.
+func extensionSchema(schema *Schema, key string) (*Schema, bool) {
+	ext, ok := schema.Extensions[key]
+	if !ok {
+		return nil, false
+	}
+	raw, err := json.Marshal(ext)
+	if err != nil {
+		return nil, false
+	}
+	var content Schema
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, false
+	}
+	return &content, true
+}
@@ -0,0 +1,125 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedJSON(t *testing.T) {
+	generator := New(WithEmbeddedJSON(), WithSchemaVersion(Draft202012))
+	generator.AddSample(`{"payload": "{\"a\": 1, \"b\": \"x\"}", "list": "[1, 2]", "text": "hello"}`)
+	generator.AddSample(`{"payload": "{\"a\": 2}", "list": "[]", "text": "{not json"}`)
+
+	schema := generator.GetCurrentSchema()
+	payload := schema.Properties["payload"]
+	if payload.Type != "string" || payload.ContentMediaType != "application/json" {
+		t.Fatalf("Expected JSON string, got %+v", payload)
+	}
+	content := payload.ContentSchema
+	if content == nil || content.Type != "object" || content.Properties["a"].Type != "integer" {
+		t.Fatalf("Expected inferred object content, got %+v", content)
+	}
+	if len(content.Required) != 1 || content.Required[0] != "a" {
+		t.Errorf("Expected only a to be required, got %v", content.Required)
+	}
+	if list := schema.Properties["list"].ContentSchema; list == nil || list.Type != "array" || list.Items.Type != "integer" {
+		t.Errorf("Expected inferred array content, got %+v", list)
+	}
+	if text := schema.Properties["text"]; text.ContentMediaType != "" || text.ContentSchema != nil {
+		t.Errorf("Expected no content for inconsistent strings, got %+v", text)
+	}
+}
+
+func TestEmbeddedJSONOlderDrafts(t *testing.T) {
+	generator := New(WithEmbeddedJSON())
+	generator.AddSample(`{"payload": "{\"a\": 1}"}`)
+
+	schemaJSON, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if !strings.Contains(schemaJSON, `"contentMediaType":"application/json","x-contentSchema":{"type":"object"`) {
+		t.Errorf("Expected x-contentSchema on draft-07, got %s", schemaJSON)
+	}
+	if strings.Contains(schemaJSON, `"contentSchema"`) {
+		t.Errorf("Expected no contentSchema keyword on draft-07, got %s", schemaJSON)
+	}
+}
+
+func TestEmbeddedJSONDisabled(t *testing.T) {
+	generator := New()
+	generator.AddSample(`{"payload": "{\"a\": 1}"}`)
+
+	payload := generator.GetCurrentSchema().Properties["payload"]
+	if payload.ContentMediaType != "" || payload.Extensions != nil {
+		t.Errorf("Expected plain string without WithEmbeddedJSON, got %+v", payload)
+	}
+}
+
+func TestEmbeddedJSONPaths(t *testing.T) {
+	generator := New(
+		WithEmbeddedJSON(),
+		WithSchemaVersion(Draft201909),
+		WithIgnorePaths("payload.debug"),
+		WithPredefinedPath("payload.id", String),
+		WithMergedOverride("payload", &Schema{Description: "encoded event"}),
+	)
+	generator.AddSample(`{"payload": "{\"id\": 1, \"debug\": true}"}`)
+
+	payload := generator.GetCurrentSchema().Properties["payload"]
+	if payload.Description != "encoded event" || payload.ContentSchema.Description != "" {
+		t.Errorf("Expected override on the string only, got %+v", payload)
+	}
+	content := payload.ContentSchema
+	if content.Properties["debug"] != nil {
+		t.Error("Expected debug to be ignored inside embedded JSON")
+	}
+	if content.Properties["id"].Type != "string" {
+		t.Errorf("Expected predefined id type, got %v", content.Properties["id"].Type)
+	}
+}
+
+func TestEmbeddedJSONLoad(t *testing.T) {
+	for _, version := range []SchemaVersion{Draft07, Draft202012} {
+		generator := New(WithEmbeddedJSON(), WithSchemaVersion(version))
+		generator.AddSample(`{"payload": "{\"a\": 1}"}`)
+		saved, err := generator.Generate()
+		if err != nil {
+			t.Fatalf("Failed to generate schema: %v", err)
+		}
+
+		restored := New(WithEmbeddedJSON(), WithSchemaVersion(Draft202012))
+		if err := restored.Load(saved); err != nil {
+			t.Fatalf("Failed to load schema: %v", err)
+		}
+		restored.AddSample(`{"payload": "{\"a\": 2, \"b\": true}"}`)
+
+		content := restored.GetCurrentSchema().Properties["payload"].ContentSchema
+		if content == nil || content.Properties["a"] == nil || content.Properties["b"] == nil {
+			t.Errorf("%s: expected loaded and new embedded properties, got %+v", version, content)
+		}
+	}
+}
+
+func TestEmbeddedJSONParallel(t *testing.T) {
+	samples := []string{
+		`{"payload": "{\"a\": 1}"}`,
+		`{"payload": "{\"a\": 2, \"b\": [1]}"}`,
+		`{"payload": "{\"b\": []}"}`,
+	}
+	sequential := New(WithEmbeddedJSON())
+	parallel := NewParallel(3, WithEmbeddedJSON())
+	for i := 0; i < 30; i++ {
+		sequential.AddSample(samples[i%3])
+		parallel.AddSample(samples[i%3])
+	}
+
+	want, _ := sequential.Generate()
+	got, err := parallel.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if got != want {
+		t.Errorf("Parallel schema differs from sequential\ngot:  %s\nwant: %s", got, want)
+	}
+}
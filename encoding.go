@@ -0,0 +1,235 @@
+package jsonschema
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// encodingClass is a bit set of the encodings a string value may be in.
+type encodingClass uint16
+
+const (
+	encJWT       encodingClass = 1 << iota // JSON Web Token
+	encGzip                                // gzip data in base64
+	encMD5                                 // 32 hex digits
+	encSHA1                                // 40 hex digits
+	encSHA256                              // 64 hex digits
+	encHex                                 // any even number of hex digits
+	encBase64                              // standard base64
+	encBase64URL                           // URL-safe base64
+)
+
+// encodingHint is how an encoding class is described in the schema.
+type encodingHint struct {
+	class     encodingClass
+	encoding  string // contentEncoding
+	mediaType string // contentMediaType
+	digest    string // x-digest
+}
+
+// encodingHints lists the classes from the most to the least specific; the
+// first one matched by every value of a field is emitted.
+var encodingHints = []encodingHint{
+	{class: encJWT, mediaType: "application/jwt"},
+	{class: encGzip, encoding: "base64", mediaType: "application/gzip"},
+	{class: encMD5, encoding: "base16", digest: "md5"},
+	{class: encSHA1, encoding: "base16", digest: "sha1"},
+	{class: encSHA256, encoding: "base16", digest: "sha256"},
+	{class: encHex, encoding: "base16"},
+	{class: encBase64, encoding: "base64"},
+	{class: encBase64URL, encoding: "base64url"},
+}
+
+// classifyEncoding returns the encodings str may be in. The checks are
+// heuristics: short values and values that look like words or numbers are
+// never reported as encoded.
+func classifyEncoding(str string) encodingClass {
+	if len(str) < 16 {
+		return 0
+	}
+
+	if strings.HasPrefix(str, "eyJ") && isJWT(str) {
+		return encJWT
+	}
+
+	var classes encodingClass
+	if isHexDigits(str) {
+		classes |= encHex
+		switch len(str) {
+		case 32:
+			classes |= encMD5
+		case 40:
+			classes |= encSHA1
+		case 64:
+			classes |= encSHA256
+		}
+		return classes
+	}
+
+	if isBase64(str) {
+		classes |= encBase64
+	}
+	if isBase64URL(str) {
+		classes |= encBase64URL
+	}
+	// gzip streams start with 1f 8b 08, which is "H4sI" in base64
+	if classes != 0 && strings.HasPrefix(str, "H4sI") {
+		if data, ok := decodeBase64(str); ok && len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+			classes |= encGzip
+		}
+	}
+	return classes
+}
+
+// isHexDigits reports whether s is an even number of hex digits in a single
+// case, with at least one letter so that numbers do not qualify.
+func isHexDigits(s string) bool {
+	if len(s)%2 != 0 {
+		return false
+	}
+	var lower, upper bool
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+		case c >= 'a' && c <= 'f':
+			lower = true
+		case c >= 'A' && c <= 'F':
+			upper = true
+		default:
+			return false
+		}
+	}
+	return lower != upper
+}
+
+// isBase64URL is isBase64 for the URL-safe alphabet, with optional padding.
+func isBase64URL(value string) bool {
+	unpadded := strings.TrimRight(value, "=")
+	if len(value)-len(unpadded) > 2 || strings.ContainsAny(value, "+/") {
+		return false
+	}
+	std := strings.NewReplacer("-", "+", "_", "/").Replace(unpadded)
+	if pad := len(std) % 4; pad != 0 {
+		std += strings.Repeat("=", 4-pad)
+	}
+	return isBase64(std)
+}
+
+// decodeBase64 decodes standard or URL-safe base64, padded or not.
+func decodeBase64(s string) ([]byte, bool) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if data, err := enc.DecodeString(s); err == nil {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// isJWT reports whether s is a JSON Web Token: three base64url segments whose
+// first one is a JSON header naming the algorithm. The signature may be empty.
+func isJWT(s string) bool {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	var header map[string]interface{}
+	if !decodeJWTSegment(parts[0], &header) || header["alg"] == nil {
+		return false
+	}
+	var claims map[string]interface{}
+	return decodeJWTSegment(parts[1], &claims)
+}
+
+// decodeJWTSegment decodes a base64url JSON segment of a JWT into v.
+func decodeJWTSegment(segment string, v interface{}) bool {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	return err == nil && json.Unmarshal(data, v) == nil
+}
+
+// observeEncoding narrows the encodings of the node down to those of str, and
+// observes the claims of JWTs when requested.
+func (n *SchemaNode) observeEncoding(str string, opts *observeOptions) {
+	classes := classifyEncoding(str)
+	if n.encodingSet {
+		n.encodings &= classes
+	} else {
+		n.encodings = classes
+		n.encodingSet = true
+	}
+
+	if n.encodings&encJWT == 0 {
+		n.jwtClaims = nil
+		return
+	}
+	if opts.jwtClaims {
+		var claims interface{}
+		decodeJWTSegment(strings.Split(str, ".")[1], &claims)
+		if n.jwtClaims == nil {
+			n.jwtClaims = NewSchemaNode()
+		}
+		// Claims are not addressed by path options
+		claimOpts := *opts
+		claimOpts.ignore, claimOpts.path = nil, nil
+		n.jwtClaims.observe(claims, &claimOpts)
+	}
+}
+
+// mergeEncoding folds the encodings observed by other into n.
+func (n *SchemaNode) mergeEncoding(other *SchemaNode) {
+	if !other.encodingSet {
+		return
+	}
+	if n.encodingSet {
+		n.encodings &= other.encodings
+	} else {
+		n.encodings = other.encodings
+		n.encodingSet = true
+	}
+
+	if n.encodings&encJWT == 0 {
+		n.jwtClaims = nil
+		return
+	}
+	if other.jwtClaims != nil {
+		if n.jwtClaims == nil {
+			n.jwtClaims = NewSchemaNode()
+		}
+		n.jwtClaims.merge(other.jwtClaims)
+	}
+}
+
+// applyEncoding describes the encoding shared by every value of the node.
+func (n *SchemaNode) applyEncoding(schema *Schema, opts *renderOptions) {
+	for _, hint := range encodingHints {
+		if n.encodings&hint.class == 0 {
+			continue
+		}
+		schema.ContentEncoding = hint.encoding
+		if hint.mediaType != "" {
+			schema.ContentMediaType = hint.mediaType
+		}
+		if hint.digest != "" {
+			schema.setExtension("x-digest", hint.digest)
+		}
+		if hint.class == encJWT && n.jwtClaims != nil {
+			schema.setExtension("x-jwt-claims", n.jwtClaims.toSchema(opts))
+		}
+		return
+	}
+}
+
+// loadedEncoding returns the encoding classes described by a loaded schema.
+func loadedEncoding(schema *Schema) encodingClass {
+	digest, _ := schema.Extensions["x-digest"].(string)
+	for _, hint := range encodingHints {
+		if hint.encoding == schema.ContentEncoding && hint.digest == digest &&
+			(hint.mediaType == "" || hint.mediaType == schema.ContentMediaType) {
+			if hint.digest != "" {
+				return hint.class | encHex
+			}
+			return hint.class
+		}
+	}
+	return 0
+}
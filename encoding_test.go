@@ -0,0 +1,180 @@
+package jsonschema
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// makeJWT returns an unsigned-looking JWT carrying claims.
+func makeJWT(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(claims)) + "." +
+		enc.EncodeToString([]byte("signature-bytes"))
+}
+
+// makeGzip returns base64-encoded gzip data.
+func makeGzip(t *testing.T, content string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func encodedSample(t *testing.T, i int) string {
+	value := fmt.Sprintf("value %d", i)
+	sample := map[string]string{
+		"token":  makeJWT(fmt.Sprintf(`{"sub":"user%d","exp":%d}`, i, 1700000000+i)),
+		"blob":   makeGzip(t, value),
+		"md5":    fmt.Sprintf("%x", md5.Sum([]byte(value))),
+		"sha1":   fmt.Sprintf("%x", sha1.Sum([]byte(value))),
+		"sha256": fmt.Sprintf("%x", sha256.Sum256([]byte(value))),
+		"hex":    hex.EncodeToString([]byte("binary" + value)),
+		"b64":    base64.StdEncoding.EncodeToString([]byte("Some Bytes " + value)),
+		"b64url": base64.RawURLEncoding.EncodeToString([]byte{0xfb, 0xff, 0xfe, byte(i), 'a', 'B', 0x10, 0x20, 0x30, 0x40, 0x50, 0x60}),
+		"text":   "Just an ordinary sentence " + value,
+		"short":  "abc",
+	}
+	data, err := json.Marshal(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestEncodedContent(t *testing.T) {
+	generator := New(WithEncodedContent())
+	for i := 0; i < 3; i++ {
+		if err := generator.AddSample(encodedSample(t, i)); err != nil {
+			t.Fatalf("Failed to add sample: %v", err)
+		}
+	}
+
+	schema := generator.GetCurrentSchema()
+	tests := []struct {
+		field, encoding, mediaType, digest string
+	}{
+		{"token", "", "application/jwt", ""},
+		{"blob", "base64", "application/gzip", ""},
+		{"md5", "base16", "", "md5"},
+		{"sha1", "base16", "", "sha1"},
+		{"sha256", "base16", "", "sha256"},
+		{"hex", "base16", "", ""},
+		{"b64", "base64", "", ""},
+		{"b64url", "base64url", "", ""},
+		{"text", "", "", ""},
+		{"short", "", "", ""},
+	}
+	for _, tt := range tests {
+		prop := schema.Properties[tt.field]
+		digest, _ := prop.Extensions["x-digest"].(string)
+		if prop.ContentEncoding != tt.encoding || prop.ContentMediaType != tt.mediaType || digest != tt.digest {
+			t.Errorf("%s: expected %q/%q/%q, got %q/%q/%q", tt.field, tt.encoding, tt.mediaType, tt.digest,
+				prop.ContentEncoding, prop.ContentMediaType, digest)
+		}
+		if prop.Extensions["x-jwt-claims"] != nil {
+			t.Errorf("%s: expected no claims without WithJWTClaims", tt.field)
+		}
+	}
+}
+
+func TestEncodedContentMixed(t *testing.T) {
+	generator := New(WithEncodedContent())
+	generator.AddSample(fmt.Sprintf(`{"digest": "%x"}`, md5.Sum([]byte("a"))))
+	generator.AddSample(fmt.Sprintf(`{"digest": "%x"}`, sha256.Sum256([]byte("a"))))
+
+	// Both are hex, but not of the same digest length.
+	prop := generator.GetCurrentSchema().Properties["digest"]
+	if prop.ContentEncoding != "base16" || prop.Extensions != nil {
+		t.Errorf("Expected plain base16, got %+v", prop)
+	}
+
+	generator.AddSample(`{"digest": "not an encoded value at all"}`)
+	if prop := generator.GetCurrentSchema().Properties["digest"]; prop.ContentEncoding != "" {
+		t.Errorf("Expected no encoding after a plain value, got %q", prop.ContentEncoding)
+	}
+}
+
+func TestJWTClaims(t *testing.T) {
+	generator := New(WithJWTClaims())
+	generator.AddSample(fmt.Sprintf(`{"token": %q}`, makeJWT(`{"sub":"alice","exp":1700000000,"admin":true}`)))
+	generator.AddSample(fmt.Sprintf(`{"token": %q}`, makeJWT(`{"sub":"bob","exp":1700000100}`)))
+
+	schemaJSON, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if !strings.Contains(schemaJSON, `"contentMediaType":"application/jwt","x-jwt-claims":{"type":"object"`) {
+		t.Errorf("Expected JWT claims schema, got %s", schemaJSON)
+	}
+
+	// Round trip through Load keeps the claims and the encoding.
+	restored := New(WithJWTClaims())
+	if err := restored.Load(schemaJSON); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	restored.AddSample(fmt.Sprintf(`{"token": %q}`, makeJWT(`{"sub":"carol","scope":"read"}`)))
+
+	token := restored.GetCurrentSchema().Properties["token"]
+	if token.ContentMediaType != "application/jwt" {
+		t.Errorf("Expected JWT media type after Load, got %q", token.ContentMediaType)
+	}
+	claims, ok := token.Extensions["x-jwt-claims"].(*Schema)
+	if !ok || claims.Properties["sub"] == nil || claims.Properties["scope"] == nil || claims.Properties["admin"] == nil {
+		t.Errorf("Expected loaded and new claims, got %#v", token.Extensions["x-jwt-claims"])
+	}
+}
+
+func TestEncodedContentLoad(t *testing.T) {
+	generator := New(WithEncodedContent())
+	err := generator.Load(`{"type": "object", "properties": {
+		"sum": {"type": "string", "contentEncoding": "base16", "x-digest": "md5"},
+		"blob": {"type": "string", "contentEncoding": "base64", "contentMediaType": "application/gzip"}
+	}, "required": ["sum", "blob"]}`)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	generator.AddSample(fmt.Sprintf(`{"sum": "%x", "blob": %q}`, md5.Sum([]byte("x")), makeGzip(t, "x")))
+
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["sum"].Extensions["x-digest"] != "md5" {
+		t.Errorf("Expected md5 digest after Load, got %+v", schema.Properties["sum"])
+	}
+	if schema.Properties["blob"].ContentMediaType != "application/gzip" {
+		t.Errorf("Expected gzip after Load, got %+v", schema.Properties["blob"])
+	}
+}
+
+func TestEncodedContentParallel(t *testing.T) {
+	sequential := New(WithJWTClaims())
+	parallel := NewParallel(3, WithJWTClaims())
+	for i := 0; i < 12; i++ {
+		sample := encodedSample(t, i)
+		sequential.AddSample(sample)
+		parallel.AddSample(sample)
+	}
+
+	want, _ := sequential.Generate()
+	got, err := parallel.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if got != want {
+		t.Errorf("Parallel schema differs from sequential\ngot:  %s\nwant: %s", got, want)
+	}
+}
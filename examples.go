@@ -0,0 +1,181 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"sort"
+	"unicode/utf8"
+)
+
+// sampledValue is a value kept by an exampleSet, with its canonical JSON text
+// and the hash of that text.
+type sampledValue struct {
+	hash  uint64
+	key   string
+	value any
+}
+
+// exampleSet keeps a bounded, diverse selection of the distinct values of a
+// node for WithExampleCount(n) and WithRootExamples(n): the smallest and largest
+// values, and the limit distinct values with the lowest hashes. Picking by hash
+// is a uniform random sample of the distinct values that does not depend on the
+// order of the values, so sets merged from ParallelGenerator shards hold the
+// same selection as a set that observed every value.
+type exampleSet struct {
+	limit             int
+	sample            []sampledValue // sorted by hash, at most limit
+	smallest, largest *sampledValue
+}
+
+// sampleValue returns the canonical JSON text of value and its hash. The value
+// itself is not copied; see own.
+func sampleValue(value any) (sampledValue, bool) {
+	text, err := json.Marshal(value)
+	if err != nil {
+		return sampledValue{}, false
+	}
+	h := fnv.New64a()
+	h.Write(text)
+	return sampledValue{hash: h.Sum64(), key: string(text), value: value}, true
+}
+
+// own replaces an object or array value with a copy made from its JSON text,
+// so later changes to a sample by the caller of AddParsedSample do not show in
+// the schema.
+func (sv *sampledValue) own() bool {
+	switch sv.value.(type) {
+	case map[string]interface{}, []interface{}:
+		var clone any
+		if json.Unmarshal([]byte(sv.key), &clone) != nil {
+			return false
+		}
+		sv.value = clone
+	}
+	return true
+}
+
+// add records one value, keeping at most limit sampled values. The value is
+// only copied when it is kept.
+func (s *exampleSet) add(value any, limit int) {
+	sv, ok := sampleValue(value)
+	if !ok {
+		return
+	}
+	s.limit = max(s.limit, limit)
+	_, sampled := s.position(sv)
+	smallest := s.smallest == nil || lessExtent(sv, *s.smallest)
+	largest := s.largest == nil || lessExtent(*s.largest, sv)
+	if !sampled && !smallest && !largest || !sv.own() {
+		return
+	}
+	if sampled {
+		s.insert(sv)
+	}
+	if smallest {
+		s.smallest = &sv
+	}
+	if largest {
+		s.largest = &sv
+	}
+}
+
+// position returns the index of sv in the hash sample, and false when it is
+// already there or its hash is too high to be kept.
+func (s *exampleSet) position(sv sampledValue) (int, bool) {
+	i := sort.Search(len(s.sample), func(i int) bool {
+		return !lessHash(s.sample[i], sv)
+	})
+	if i < len(s.sample) && s.sample[i].key == sv.key {
+		return i, false
+	}
+	return i, i < s.limit
+}
+
+// insert adds sv to the hash sample unless it is already there or its hash is
+// too high to be kept.
+func (s *exampleSet) insert(sv sampledValue) {
+	i, ok := s.position(sv)
+	if !ok {
+		return
+	}
+	s.sample = append(s.sample, sampledValue{})
+	copy(s.sample[i+1:], s.sample[i:])
+	s.sample[i] = sv
+	if len(s.sample) > s.limit {
+		s.sample = s.sample[:s.limit]
+	}
+}
+
+// lessHash orders sampled values by hash, then by text.
+func lessHash(a, b sampledValue) bool {
+	if a.hash != b.hash {
+		return a.hash < b.hash
+	}
+	return a.key < b.key
+}
+
+// lessExtent orders values by extent: numbers by value, strings by length,
+// anything else by the length of its JSON text. Ties are broken by text.
+func lessExtent(a, b sampledValue) bool {
+	if fa, ok := a.value.(float64); ok {
+		if fb, ok := b.value.(float64); ok && fa != fb {
+			return fa < fb
+		}
+	}
+	la, lb := len(a.key), len(b.key)
+	if sa, ok := a.value.(string); ok {
+		la = utf8.RuneCountInString(sa)
+	}
+	if sb, ok := b.value.(string); ok {
+		lb = utf8.RuneCountInString(sb)
+	}
+	if la != lb {
+		return la < lb
+	}
+	return a.key < b.key
+}
+
+// merge folds the values of other into s.
+func (s *exampleSet) merge(other *exampleSet) {
+	s.limit = max(s.limit, other.limit)
+	for _, sv := range other.sample {
+		s.insert(sv)
+	}
+	if other.smallest != nil && (s.smallest == nil || lessExtent(*other.smallest, *s.smallest)) {
+		s.smallest = other.smallest
+	}
+	if other.largest != nil && (s.largest == nil || lessExtent(*s.largest, *other.largest)) {
+		s.largest = other.largest
+	}
+}
+
+// examples returns up to limit distinct values: the most frequent value in
+// counts when it occurred more than once, the smallest and largest values, then
+// the hash sample.
+func (s *exampleSet) examples(limit int, counts *valueCounter) []any {
+	var candidates []sampledValue
+	if counts != nil {
+		if value, count := counts.top(); count > 1 {
+			if sv, ok := sampleValue(value); ok {
+				candidates = append(candidates, sv)
+			}
+		}
+	}
+	if s.smallest != nil {
+		candidates = append(candidates, *s.smallest, *s.largest)
+	}
+	candidates = append(candidates, s.sample...)
+
+	var examples []any
+	seen := make(map[string]bool, len(candidates))
+	for _, sv := range candidates {
+		if len(examples) == limit {
+			break
+		}
+		if !seen[sv.key] {
+			seen[sv.key] = true
+			examples = append(examples, sv.value)
+		}
+	}
+	return examples
+}
@@ -2,6 +2,8 @@ package jsonschema
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
 	"testing"
 )
 
@@ -32,3 +34,120 @@ func TestWithExamples(t *testing.T) {
 		}
 	})
 }
+
+func TestWithExampleCount(t *testing.T) {
+	generator := New(WithExampleCount(3))
+	for _, name := range []string{"Al", "Bob", "Bob", "Bob", "Christopher", "Dan"} {
+		generator.AddSample(`{"name": "` + name + `", "age": 30, "tags": ["a"]}`)
+	}
+	generator.AddSample(`{"name": "Eve", "age": 7, "tags": ["b"]}`)
+	generator.AddSample(`{"name": "Bob", "age": 92, "tags": []}`)
+
+	schema := generator.GetCurrentSchema()
+	name := schema.Properties["name"]
+	if name.Example != nil {
+		t.Errorf("Expected no single example with WithExampleCount(n), got %v", name.Example)
+	}
+	want := []any{"Bob", "Al", "Christopher"}
+	if len(name.Examples) != len(want) {
+		t.Fatalf("Expected examples %v, got %v", want, name.Examples)
+	}
+	for i := range want {
+		if name.Examples[i] != want[i] {
+			t.Errorf("Expected examples %v, got %v", want, name.Examples)
+			break
+		}
+	}
+
+	age := schema.Properties["age"].Examples
+	if len(age) != 3 || age[0] != 30.0 || age[1] != 7.0 || age[2] != 92.0 {
+		t.Errorf("Expected most frequent, smallest and largest ages, got %v", age)
+	}
+	if tags := schema.Properties["tags"]; tags.Examples != nil || tags.Items.Examples == nil {
+		t.Errorf("Expected examples on array items only, got %+v", tags)
+	}
+}
+
+func TestWithExampleCountBounded(t *testing.T) {
+	generator := New(WithExampleCount(2))
+	for i := 0; i < 500; i++ {
+		generator.AddSample(fmt.Sprintf(`{"id": %d}`, i%10))
+	}
+	node := generator.rootNode.objectProperties["id"]
+	if len(node.samples.sample) > 2 {
+		t.Errorf("Expected at most 2 sampled values, got %d", len(node.samples.sample))
+	}
+	if examples := generator.GetCurrentSchema().Properties["id"].Examples; len(examples) != 2 {
+		t.Errorf("Expected 2 examples, got %v", examples)
+	}
+}
+
+func TestWithRootExamples(t *testing.T) {
+	generator := New(WithRootExamples(2))
+	generator.AddSample(`{"id": 1}`)
+	generator.AddSample(`{"id": 2, "name": "a much longer sample"}`)
+	generator.AddSample(`{"id": 1}`)
+
+	schema := generator.GetCurrentSchema()
+	if len(schema.Examples) != 2 {
+		t.Fatalf("Expected 2 root examples, got %v", schema.Examples)
+	}
+	first, ok := schema.Examples[0].(map[string]interface{})
+	if !ok || first["id"] != 1.0 || len(first) != 1 {
+		t.Errorf("Expected smallest sample first, got %v", schema.Examples[0])
+	}
+	if schema.Properties["id"].Examples != nil {
+		t.Errorf("Expected no field examples with WithRootExamples only, got %v", schema.Properties["id"].Examples)
+	}
+}
+
+func TestWithExampleCountLoadAndParallel(t *testing.T) {
+	samples := []string{`{"v": "x"}`, `{"v": "yy"}`, `{"v": "zzz"}`, `{"v": "x"}`}
+	sequential := New(WithExampleCount(3), WithRootExamples(2))
+	parallel := NewParallel(2, WithExampleCount(3), WithRootExamples(2))
+	for _, sample := range samples {
+		sequential.AddSample(sample)
+		parallel.AddSample(sample)
+	}
+	want, _ := sequential.Generate()
+	got, err := parallel.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if got != want {
+		t.Errorf("Parallel schema differs from sequential\ngot:  %s\nwant: %s", got, want)
+	}
+
+	restored := New(WithExampleCount(3), WithRootExamples(2))
+	if err := restored.Load(want); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	restored.AddSample(`{"v": "wwww"}`)
+	schema := restored.GetCurrentSchema()
+	if examples := schema.Properties["v"].Examples; len(examples) != 3 || !slices.Contains(examples, any("wwww")) {
+		t.Errorf("Expected loaded and new examples, got %v", examples)
+	}
+	if len(schema.Examples) != 2 {
+		t.Errorf("Expected 2 root examples after load, got %v", schema.Examples)
+	}
+}
+
+func TestWithRootExamplesCopied(t *testing.T) {
+	generator := New(WithRootExamples(1))
+	sample := map[string]interface{}{"id": 1.0}
+	generator.AddParsedSample(sample)
+	sample["id"] = 2.0
+
+	if got := generator.GetCurrentSchema().Examples; len(got) != 1 || got[0].(map[string]interface{})["id"] != 1.0 {
+		t.Errorf("Expected the kept root example to be a copy, got %v", got)
+	}
+}
+
+func TestWithExampleCountZeroDisabled(t *testing.T) {
+	generator := New(WithExampleCount(0))
+	generator.AddSample(`{"name": "John"}`)
+
+	if prop := generator.GetCurrentSchema().Properties["name"]; prop.Examples != nil || prop.Example != nil {
+		t.Errorf("Expected no examples with WithExampleCount(0), got %+v", prop)
+	}
+}
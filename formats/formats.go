@@ -0,0 +1,204 @@
+// Package formats provides an opt-in pack of domain-specific string formats
+// for the jsonschema generator, for use with jsonschema.WithFormatPack:
+//
+//	generator := jsonschema.New(jsonschema.WithFormatPack(formats.Common))
+//
+// Every detector works offline: the ISO code lists are embedded in the package.
+package formats
+
+import (
+	"embed"
+	"net"
+	"regexp"
+	"strings"
+
+	jsonschema "github.com/JLugagne/jsonschema-infer"
+)
+
+//go:embed data/*.txt
+var data embed.FS
+
+var (
+	// ISO 4217 currency codes
+	currencies = loadCodes("data/currencies.txt")
+	// ISO 3166-1 alpha-2 and alpha-3 country codes
+	countries = loadCodes("data/countries.txt")
+	// ISO 639-1 language codes
+	languages = loadCodes("data/languages.txt")
+
+	// E.164: '+', country code and subscriber number, 15 digits at most
+	phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+	// #RGB, #RRGGBB or #RRGGBBAA
+	hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+	// Semantic Versioning 2.0.0, from semver.org
+	semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+)
+
+// Formats of the pack. They can be registered one by one with jsonschema.WithFormat.
+var (
+	// Phone matches E.164 phone numbers such as "+14155552671".
+	Phone = jsonschema.CustomFormat{Name: "phone", Detector: IsPhone}
+	// HexColor matches "#RRGGBB" colours, also "#RGB" and "#RRGGBBAA".
+	HexColor = jsonschema.CustomFormat{Name: "hex-color", Detector: IsHexColor}
+	// Currency matches ISO 4217 currency codes such as "EUR".
+	Currency = jsonschema.CustomFormat{Name: "currency", Detector: IsCurrency}
+	// Country matches ISO 3166-1 alpha-2 or alpha-3 country codes such as "FR" or "FRA".
+	Country = jsonschema.CustomFormat{Name: "country-code", Detector: IsCountry}
+	// Language matches BCP 47 language tags such as "en", "en-US" or "zh-Hant-TW".
+	Language = jsonschema.CustomFormat{Name: "language", Detector: IsLanguage}
+	// SemVer matches semantic versions such as "1.4.0-rc.1".
+	SemVer = jsonschema.CustomFormat{Name: "semver", Detector: IsSemVer}
+	// MAC matches MAC addresses such as "00:1a:2b:3c:4d:5e".
+	MAC = jsonschema.CustomFormat{Name: "mac", Detector: IsMAC}
+	// CIDR matches IPv4 and IPv6 CIDR blocks such as "10.0.0.0/8".
+	CIDR = jsonschema.CustomFormat{Name: "cidr", Detector: IsCIDR}
+)
+
+// Common is the whole pack. Currency codes are checked before alpha-3 country
+// codes, so the few codes that are both ("CHE", "MKD") count as currencies.
+var Common = []jsonschema.CustomFormat{Phone, HexColor, Currency, Country, Language, SemVer, MAC, CIDR}
+
+// IsPhone checks if a string value is an E.164 phone number.
+func IsPhone(value string) bool {
+	return len(value) >= 8 && value[0] == '+' && phonePattern.MatchString(value)
+}
+
+// IsHexColor checks if a string value is a hexadecimal colour.
+func IsHexColor(value string) bool {
+	return len(value) >= 4 && value[0] == '#' && hexColorPattern.MatchString(value)
+}
+
+// IsCurrency checks if a string value is an ISO 4217 currency code.
+func IsCurrency(value string) bool {
+	return len(value) == 3 && currencies[value]
+}
+
+// IsCountry checks if a string value is an upper-case ISO 3166-1 alpha-2 or
+// alpha-3 country code.
+func IsCountry(value string) bool {
+	return (len(value) == 2 || len(value) == 3) && countries[value]
+}
+
+// IsLanguage checks if a string value is a BCP 47 language tag made of a
+// lower-case ISO 639-1 language, then optionally a script ("Hant"), a region
+// ("US", "419") and variants. Extensions and private-use subtags are not
+// recognised.
+func IsLanguage(value string) bool {
+	subtags := strings.Split(value, "-")
+	if !languages[subtags[0]] {
+		return false
+	}
+	subtags = subtags[1:]
+	if len(subtags) > 0 && len(subtags[0]) == 4 && isAlpha(subtags[0]) {
+		subtags = subtags[1:]
+	}
+	if len(subtags) > 0 && (len(subtags[0]) == 2 && countries[strings.ToUpper(subtags[0])] ||
+		len(subtags[0]) == 3 && isDigits(subtags[0])) {
+		subtags = subtags[1:]
+	}
+	for _, variant := range subtags {
+		if !isVariant(variant) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSemVer checks if a string value is a semantic version (without a "v" prefix).
+func IsSemVer(value string) bool {
+	return len(value) >= 5 && semverPattern.MatchString(value)
+}
+
+// IsMAC checks if a string value is a 48-bit MAC address with ':' or '-' separators.
+func IsMAC(value string) bool {
+	if len(value) != 17 || (value[2] != ':' && value[2] != '-') {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if i%3 == 2 {
+			if value[i] != value[2] {
+				return false
+			}
+		} else if !isHex(value[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsCIDR checks if a string value is an IPv4 or IPv6 CIDR block.
+func IsCIDR(value string) bool {
+	if !strings.ContainsRune(value, '/') {
+		return false
+	}
+	_, _, err := net.ParseCIDR(value)
+	return err == nil
+}
+
+// isVariant checks if s is a BCP 47 variant subtag: 5 to 8 alphanumerics, or a
+// digit followed by 3 alphanumerics.
+func isVariant(s string) bool {
+	switch {
+	case len(s) >= 5 && len(s) <= 8:
+		return isAlnum(s)
+	case len(s) == 4:
+		return s[0] >= '0' && s[0] <= '9' && isAlnum(s)
+	}
+	return false
+}
+
+// isHex checks if c is a hexadecimal digit, in either case.
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c|0x20 >= 'a' && c|0x20 <= 'f'
+}
+
+// isAlpha checks if s is made of ASCII letters only.
+func isAlpha(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits checks if s is made of ASCII digits only.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isAlnum checks if s is made of ASCII letters and digits only.
+func isAlnum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c|0x20 < 'a' || c|0x20 > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// loadCodes reads an embedded code list: whitespace-separated codes, with
+// lines starting with '#' ignored.
+func loadCodes(name string) map[string]bool {
+	content, err := data.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+	codes := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, code := range strings.Fields(line) {
+			codes[code] = true
+		}
+	}
+	return codes
+}
@@ -0,0 +1,106 @@
+package formats
+
+import (
+	"fmt"
+	"testing"
+
+	jsonschema "github.com/JLugagne/jsonschema-infer"
+)
+
+func TestDetectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		detector func(string) bool
+		value    string
+		want     bool
+	}{
+		{"phone", IsPhone, "+14155552671", true},
+		{"phone", IsPhone, "+33123456789", true},
+		{"phone", IsPhone, "14155552671", false},
+		{"phone", IsPhone, "+0123456789", false},
+		{"phone", IsPhone, "+1234567890123456", false},
+		{"hex-color", IsHexColor, "#FF5733", true},
+		{"hex-color", IsHexColor, "#fff", true},
+		{"hex-color", IsHexColor, "#11223344", true},
+		{"hex-color", IsHexColor, "#GG0000", false},
+		{"hex-color", IsHexColor, "FF5733", false},
+		{"currency", IsCurrency, "EUR", true},
+		{"currency", IsCurrency, "XAU", true},
+		{"currency", IsCurrency, "eur", false},
+		{"currency", IsCurrency, "ABC", false},
+		{"country-code", IsCountry, "FR", true},
+		{"country-code", IsCountry, "USA", true},
+		{"country-code", IsCountry, "fr", false},
+		{"country-code", IsCountry, "XX", false},
+		{"language", IsLanguage, "en", true},
+		{"language", IsLanguage, "en-US", true},
+		{"language", IsLanguage, "es-419", true},
+		{"language", IsLanguage, "zh-Hant-TW", true},
+		{"language", IsLanguage, "sl-rozaj-biske", true},
+		{"language", IsLanguage, "de-1996", true},
+		{"language", IsLanguage, "EN", false},
+		{"language", IsLanguage, "en-XX", false},
+		{"language", IsLanguage, "english", false},
+		{"language", IsLanguage, "en-", false},
+		{"semver", IsSemVer, "1.4.0", true},
+		{"semver", IsSemVer, "1.0.0-rc.1+build.5", true},
+		{"semver", IsSemVer, "v1.4.0", false},
+		{"semver", IsSemVer, "1.04.0", false},
+		{"semver", IsSemVer, "1.4", false},
+		{"mac", IsMAC, "00:1a:2b:3c:4d:5e", true},
+		{"mac", IsMAC, "00-1A-2B-3C-4D-5E", true},
+		{"mac", IsMAC, "00:1a-2b:3c:4d:5e", false},
+		{"mac", IsMAC, "001a.2b3c.4d5e", false},
+		{"cidr", IsCIDR, "10.0.0.0/8", true},
+		{"cidr", IsCIDR, "2001:db8::/32", true},
+		{"cidr", IsCIDR, "10.0.0.0", false},
+		{"cidr", IsCIDR, "10.0.0.0/33", false},
+	}
+	for _, tt := range tests {
+		if got := tt.detector(tt.value); got != tt.want {
+			t.Errorf("%s(%q) = %v, want %v", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCodeLists(t *testing.T) {
+	if len(currencies) < 150 || len(countries) != 2*249 || len(languages) != 184 {
+		t.Errorf("Unexpected code list sizes: %d currencies, %d countries, %d languages",
+			len(currencies), len(countries), len(languages))
+	}
+}
+
+func TestCommonPack(t *testing.T) {
+	generator := jsonschema.New(jsonschema.WithFormatPack(Common))
+	for i := 0; i < 3; i++ {
+		generator.AddSample(fmt.Sprintf(`{
+			"phone": "+1415555267%d",
+			"color": "#00FF0%d",
+			"currency": "EUR",
+			"country": "FRA",
+			"lang": "fr-CA",
+			"version": "1.%d.0",
+			"mac": "00:1a:2b:3c:4d:5%d",
+			"subnet": "10.%d.0.0/16",
+			"website": "https://example.com"
+		}`, i, i, i, i, i))
+	}
+
+	schema := generator.GetCurrentSchema()
+	expected := map[string]string{
+		"phone":    "phone",
+		"color":    "hex-color",
+		"currency": "currency",
+		"country":  "country-code",
+		"lang":     "language",
+		"version":  "semver",
+		"mac":      "mac",
+		"subnet":   "cidr",
+		"website":  "uri",
+	}
+	for field, format := range expected {
+		if got := schema.Properties[field].Format; got != format {
+			t.Errorf("Expected %s format %q, got %q", field, format, got)
+		}
+	}
+}
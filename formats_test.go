@@ -0,0 +1,139 @@
+package jsonschema
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNetworkFormatDetectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		detector func(string) bool
+		value    string
+		want     bool
+	}{
+		{"iri", isIRI, "https://bücher.example/straße", true},
+		{"iri", isIRI, "s3://bucket/key", true},
+		{"iri", isIRI, "user:42", false},
+		{"iri", isIRI, "https://example.com/a b", false},
+		{"uri-reference", isURIReference, "/users/42?expand=true#top", true},
+		{"uri-reference", isURIReference, "../up", true},
+		{"uri-reference", isURIReference, "https://example.com", true},
+		{"uri-reference", isURIReference, "N/A", false},
+		{"uri-reference", isURIReference, "#FF5733", false},
+		{"uri-reference", isURIReference, "/bücher", false},
+		{"uri-template", isURITemplate, "/users/{id}", true},
+		{"uri-template", isURITemplate, "https://api.example.com/{+path}{?q,page}", true},
+		{"uri-template", isURITemplate, "/search{?q:3,list*}", true},
+		{"uri-template", isURITemplate, "/users/id", false},
+		{"uri-template", isURITemplate, "/users/{id", false},
+		{"uri-template", isURITemplate, "/users/{}", false},
+		{"uri-template", isURITemplate, "{name} is {age} years old", false},
+		{"json-pointer", isJSONPointer, "/definitions/user", true},
+		{"json-pointer", isJSONPointer, "/a~1b/c~0d", true},
+		{"json-pointer", isJSONPointer, "/a~b", false},
+		{"json-pointer", isJSONPointer, "a/b", false},
+		{"relative-json-pointer", isRelativeJSONPointer, "0/name", true},
+		{"relative-json-pointer", isRelativeJSONPointer, "1#", true},
+		{"relative-json-pointer", isRelativeJSONPointer, "42", false},
+		{"relative-json-pointer", isRelativeJSONPointer, "01/a", false},
+		{"relative-json-pointer", isRelativeJSONPointer, "2/items/0", true},
+		{"relative-json-pointer", isRelativeJSONPointer, "2024/01/15", false},
+		{"relative-json-pointer", isRelativeJSONPointer, "3/4", false},
+		{"relative-json-pointer", isRelativeJSONPointer, "10/20", false},
+		{"relative-json-pointer", isRelativeJSONPointer, "1/", false},
+		{"hostname", isHostname, "api.example.com", true},
+		{"hostname", isHostname, "example.com.", true},
+		{"hostname", isHostname, "localhost", false},
+		{"hostname", isHostname, "10.0.0.1", false},
+		{"hostname", isHostname, "-bad.example.com", false},
+		{"hostname", isHostname, "bücher.example", false},
+		{"idn-hostname", isIDNHostname, "bücher.example", true},
+		{"idn-hostname", isIDNHostname, "例え.テスト", true},
+		{"idn-hostname", isIDNHostname, "api.example.com", true},
+		{"idn-hostname", isIDNHostname, "hello world.com", false},
+		{"regex", isRegex, "^[a-z]+$", true},
+		{"regex", isRegex, "\\.go$", true},
+		{"regex", isRegex, "^(unclosed", false},
+		{"regex", isRegex, "hello", false},
+		{"regex", isRegex, "^abc$", true},
+		{"regex", isRegex, "5$", false},
+		{"regex", isRegex, "10$", false},
+		{"regex", isRegex, "^top", false},
+		{"byte", isBase64, "SGVsbG8sIFdvcmxkIQ==", true},
+		{"byte", isBase64, "passwordpassword", false},
+		{"byte", isBase64, "0123456789abcdef", false},
+		{"byte", isBase64, "SGVsbG8sIFdvcmxkIQ=", false},
+	}
+	for _, tt := range tests {
+		if got := tt.detector(tt.value); got != tt.want {
+			t.Errorf("%s(%q) = %v, want %v", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkFormatPrecedence(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{[]string{"https://example.com", "http://test.org/path"}, "uri"},
+		{[]string{"https://example.com", "https://bücher.example/"}, "iri"},
+		{[]string{"https://example.com", "/relative/path"}, "uri-reference"},
+		{[]string{"/definitions/user", "/properties/name"}, "uri-reference"},
+		{[]string{"/definitions/user", "/a b/~0"}, "json-pointer"},
+		{[]string{"https://api.example.com/users/{id}", "/orders/{id}/items{?page}"}, "uri-template"},
+		{[]string{"0/name", "2#"}, "relative-json-pointer"},
+		{[]string{"api.example.com", "example.org"}, "hostname"},
+		{[]string{"api.example.com", "bücher.example"}, "idn-hostname"},
+		{[]string{"^[a-z]+$", "^\\d{3}-\\d{4}$"}, "regex"},
+		{[]string{"SGVsbG8sIFdvcmxkIQ==", "U29tZSBCeXRlcw=="}, "byte"},
+		{[]string{"user@example.com", "example.com"}, ""},
+	}
+	for _, tt := range tests {
+		generator := New(WithFormatPack(HeuristicFormats))
+		for _, value := range tt.values {
+			generator.AddSample(fmt.Sprintf(`{"v": %q}`, value))
+		}
+		if got := generator.GetCurrentSchema().Properties["v"].Format; got != tt.want {
+			t.Errorf("%v: expected format %q, got %q", tt.values, tt.want, got)
+		}
+	}
+}
+
+func TestHeuristicFormatsNotDetectedByDefault(t *testing.T) {
+	generator := New()
+	generator.AddSample(`{"file": "report.pdf", "user": "john.doe", "ver": "v1.beta", "code": "Abcd1234EFgh", "host": "api.example.com", "blob": "SGVsbG8sIFdvcmxkIQ=="}`)
+
+	schema := generator.GetCurrentSchema()
+	for _, field := range []string{"file", "user", "ver", "code", "host", "blob"} {
+		if got := schema.Properties[field].Format; got != "" {
+			t.Errorf("%s: expected no format, got %q", field, got)
+		}
+	}
+}
+
+func TestHeuristicFormatsRestoredByLoad(t *testing.T) {
+	generator := New()
+	if err := generator.Load(`{"type":"object","properties":{"host":{"type":"string","format":"hostname"}},"required":["host"]}`); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	generator.AddSample(`{"host": "not a host"}`)
+
+	if got := generator.GetCurrentSchema().Properties["host"].Format; got != "" {
+		t.Errorf("expected hostname to be checked against new values, got format %q", got)
+	}
+}
+
+func TestNumericPathsAndPricesHaveNoFormat(t *testing.T) {
+	generator := New()
+	generator.AddSample(`{"date": "2024/01/15", "ratio": "3/4", "price": "5$"}`)
+	generator.AddSample(`{"date": "2024/02/01", "ratio": "10/20", "price": "10$"}`)
+
+	schema := generator.GetCurrentSchema()
+	for _, field := range []string{"date", "ratio", "price"} {
+		if got := schema.Properties[field].Format; got != "" {
+			t.Errorf("%s: expected no format, got %q", field, got)
+		}
+	}
+}
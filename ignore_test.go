@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIgnorePaths(t *testing.T) {
+	generator := New(WithIgnorePaths("debug", "**._metadata", "/events/*/trace"))
+	generator.AddSample(`{
+		"id": 1,
+		"debug": {"stack": ["a", "b"], "timings": {"db": 1.5}},
+		"user": {"name": "John", "_metadata": {"v": 1}},
+		"events": [{"kind": "click", "trace": {"span": "x"}}]
+	}`)
+
+	schemaJSON, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	for _, hidden := range []string{"debug", "stack", "_metadata", "trace", "span"} {
+		if strings.Contains(schemaJSON, `"`+hidden+`"`) {
+			t.Errorf("Expected %q to be excluded from %s", hidden, schemaJSON)
+		}
+	}
+
+	var schema Schema
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		t.Fatalf("Failed to unmarshal schema: %v", err)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"events", "id", "user"}) {
+		t.Errorf("Expected ignored fields out of required, got %v", schema.Required)
+	}
+	if schema.Properties["events"].Items.Properties["kind"] == nil {
+		t.Error("Expected siblings of ignored fields to be inferred")
+	}
+
+	// Ignored subtrees are not descended into.
+	debug := generator.rootNode.objectProperties["debug"]
+	if debug == nil || len(debug.objectProperties) != 0 || debug.observedTypes["object"] != 0 {
+		t.Errorf("Expected debug node to record nothing, got %+v", debug)
+	}
+}
+
+func TestIgnorePathsPlaceholders(t *testing.T) {
+	generator := New(WithIgnorePaths("payload", "tags[]"), WithIgnoredPlaceholders())
+	generator.AddSample(`{"id": 1, "payload": {"a": 1}, "tags": ["x"]}`)
+	generator.AddSample(`{"id": 2, "tags": []}`)
+
+	schemaJSON, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(schemaJSON), &raw); err != nil {
+		t.Fatalf("Failed to unmarshal schema: %v", err)
+	}
+	props := raw["properties"].(map[string]any)
+
+	if payload, ok := props["payload"].(map[string]any); !ok || len(payload) != 0 {
+		t.Errorf("Expected payload placeholder {}, got %v", props["payload"])
+	}
+	if items, ok := props["tags"].(map[string]any)["items"].(map[string]any); !ok || len(items) != 0 {
+		t.Errorf("Expected tags items placeholder {}, got %v", props["tags"])
+	}
+	// The placeholder keeps accurate presence: payload is missing from one sample.
+	if !reflect.DeepEqual(raw["required"], []any{"id", "tags"}) {
+		t.Errorf("Expected required [id tags], got %v", raw["required"])
+	}
+}
+
+func TestIgnorePathsParallel(t *testing.T) {
+	parallel := NewParallel(2, WithIgnorePaths("secret"), WithIgnoredPlaceholders())
+	parallel.AddSample(`{"secret": {"k": 1}}`)
+	parallel.AddSample(`{"secret": {"k": 2}}`)
+
+	schema := parallel.GetCurrentSchema()
+	if secret := schema.Properties["secret"]; secret == nil || secret.Type != nil || secret.Properties != nil {
+		t.Errorf("Expected secret placeholder, got %+v", secret)
+	}
+}
+
+func TestIgnorePathsPlaceholdersLoad(t *testing.T) {
+	generator := New(WithIgnorePaths("payload"), WithIgnoredPlaceholders())
+	generator.AddSample(`{"id": 1, "payload": {"a": 1}}`)
+	schemaJSON, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	restored := New(WithIgnorePaths("payload"), WithIgnoredPlaceholders())
+	if err := restored.Load(schemaJSON); err != nil {
+		t.Fatalf("Failed to load schema with placeholders: %v", err)
+	}
+	restored.AddSample(`{"id": 2, "payload": {"b": "x"}}`)
+	restored.AddSample(`{"id": 3}`)
+
+	schema := restored.GetCurrentSchema()
+	if payload := schema.Properties["payload"]; payload == nil || payload.Type != nil || payload.Properties != nil {
+		t.Errorf("Expected payload placeholder after Load, got %+v", payload)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"id"}) {
+		t.Errorf("Expected required [id], got %v", schema.Required)
+	}
+}
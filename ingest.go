@@ -0,0 +1,338 @@
+package jsonschema
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// IngestOption is a functional option for configuring bulk ingestion
+// (AddNDJSON and friends).
+type IngestOption func(*ingestConfig)
+
+// ingestConfig holds the settings for a single bulk ingestion call.
+type ingestConfig struct {
+	stopOnError bool
+	maxErrors   int // maximum number of errors kept in the report; 0 = unlimited
+	maxLineSize int // maximum accepted line length in bytes; 0 = unlimited
+}
+
+// StopOnError makes bulk ingestion stop at the first line that cannot be parsed.
+// The parse error is recorded in the report and also returned as the error.
+func StopOnError() IngestOption {
+	return func(c *ingestConfig) {
+		c.stopOnError = true
+	}
+}
+
+// ContinueOnError makes bulk ingestion record unparsable lines in the report and
+// carry on with the next line. This is the default.
+func ContinueOnError() IngestOption {
+	return func(c *ingestConfig) {
+		c.stopOnError = false
+	}
+}
+
+// WithMaxReportedErrors caps the number of errors kept in IngestReport.Errors.
+// Rejected lines are still counted once the cap is reached.
+// By default every error is kept.
+func WithMaxReportedErrors(max int) IngestOption {
+	return func(c *ingestConfig) {
+		c.maxErrors = max
+	}
+}
+
+// WithMaxLineSize rejects lines longer than max bytes without parsing them.
+// By default lines of any length are accepted.
+func WithMaxLineSize(max int) IngestOption {
+	return func(c *ingestConfig) {
+		c.maxLineSize = max
+	}
+}
+
+// ErrLineTooLong is reported for lines exceeding the limit set by WithMaxLineSize.
+var ErrLineTooLong = errors.New("line too long")
+
+// IngestError describes a sample rejected during bulk ingestion.
+type IngestError struct {
+	Line   int   // 1-based line number of the rejected sample; 0 when not line-based
+	Index  int   // 1-based position of the rejected sample among the samples read
+	Offset int64 // byte offset of the start of the rejected sample; 0 when not reading bytes
+	Err    error // underlying parse error
+}
+
+// Error implements the error interface.
+func (e *IngestError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("sample %d: %v", e.Index, e.Err)
+	}
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (e *IngestError) Unwrap() error {
+	return e.Err
+}
+
+// IngestReport summarises the outcome of a bulk ingestion call.
+type IngestReport struct {
+	Accepted int           // samples successfully added to the generator
+	Rejected int           // samples that could not be parsed
+	Skipped  int           // blank lines that were ignored
+	Errors   []IngestError // details of rejected samples, capped by WithMaxReportedErrors
+}
+
+// reject records a rejected sample in the report, honouring the error cap.
+func (r *IngestReport) reject(e IngestError, cfg *ingestConfig) {
+	r.Rejected++
+	if cfg.maxErrors <= 0 || len(r.Errors) < cfg.maxErrors {
+		r.Errors = append(r.Errors, e)
+	}
+}
+
+// newIngestConfig applies opts on top of the default ingestion settings.
+func newIngestConfig(opts []IngestOption) *ingestConfig {
+	cfg := &ingestConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// AddNDJSON reads newline-delimited JSON (JSON Lines) from r and adds every
+// line as a separate sample. Lines may be arbitrarily long unless limited with
+// WithMaxLineSize; blank lines are skipped. Unparsable lines are recorded in
+// the returned report and, unless StopOnError is given, ingestion continues
+// with the next line.
+//
+// The returned error is non-nil when reading from r fails, or when StopOnError
+// is set and a line is rejected; the report always reflects the progress made.
+// Thread-safe: each line is added atomically, like AddParsedSample.
+func (g *Generator) AddNDJSON(r io.Reader, opts ...IngestOption) (IngestReport, error) {
+	return g.AddNDJSONContext(context.Background(), r, opts...)
+}
+
+// AddNDJSONContext is like AddNDJSON but stops before the next line once ctx is
+// done, returning the report of the lines processed so far and ctx.Err().
+// Cancellation is checked between lines: a Read blocked on r is not interrupted.
+func (g *Generator) AddNDJSONContext(ctx context.Context, r io.Reader, opts ...IngestOption) (IngestReport, error) {
+	cfg := newIngestConfig(opts)
+	br := bufio.NewReader(r)
+
+	var report IngestReport
+	var offset int64
+	for lineNo := 1; ; lineNo++ {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		line, tooLong, n, readErr := readLine(br, cfg.maxLineSize)
+		start := offset
+		offset += n
+
+		if readErr != nil && readErr != io.EOF {
+			return report, fmt.Errorf("failed to read line %d: %w", lineNo, readErr)
+		}
+
+		var parseErr error
+		switch {
+		case tooLong:
+			parseErr = ErrLineTooLong
+		case len(bytes.TrimSpace(line)) == 0:
+			// A final empty "line" after the last newline is not a blank line.
+			if n > 0 {
+				report.Skipped++
+			}
+		default:
+			var data interface{}
+			if data, parseErr = g.parseSample(line); parseErr == nil {
+				if err := g.AddParsedSample(data); err != nil {
+					return report, fmt.Errorf("line %d: %w", lineNo, err)
+				}
+				report.Accepted++
+			}
+		}
+
+		if parseErr != nil {
+			ierr := IngestError{Line: lineNo, Index: report.Accepted + report.Rejected + 1, Offset: start, Err: parseErr}
+			report.reject(ierr, cfg)
+			if cfg.stopOnError {
+				return report, &ierr
+			}
+		}
+
+		if readErr == io.EOF {
+			return report, nil
+		}
+	}
+}
+
+// readLine reads a single line from br without the trailing newline.
+// It returns the line, whether it exceeded maxSize (in which case the rest of the
+// line is discarded and not returned), and the number of bytes consumed.
+func readLine(br *bufio.Reader, maxSize int) (line []byte, tooLong bool, n int64, err error) {
+	for {
+		chunk, readErr := br.ReadSlice('\n')
+		n += int64(len(chunk))
+		if !tooLong {
+			if maxSize > 0 && len(line)+len(bytes.TrimRight(chunk, "\r\n")) > maxSize {
+				tooLong = true
+				line = nil
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if readErr == bufio.ErrBufferFull {
+			continue
+		}
+		return bytes.TrimRight(line, "\r\n"), tooLong, n, readErr
+	}
+}
+
+// AddJSONStream reads a stream of JSON values from r and adds each record as a
+// separate sample, without loading the whole input in memory. Two layouts are
+// supported, chosen from the first non-whitespace byte of the stream:
+//
+//   - a top-level array (`[{...}, {...}]`): every element of the array is a
+//     sample, rather than the array itself as AddSample would do. Several
+//     top-level arrays may follow each other.
+//   - concatenated values (`{...}{...}` or `{...} {...}`, with or without
+//     newlines): every value is a sample.
+//
+// Unlike AddNDJSON, a malformed value cannot be skipped because the position of
+// the next value is unknown, so the first parse error always ends ingestion; it
+// is recorded in the report and returned as an *IngestError.
+// Thread-safe: each sample is added atomically, like AddParsedSample.
+func (g *Generator) AddJSONStream(r io.Reader, opts ...IngestOption) (IngestReport, error) {
+	return g.AddJSONStreamContext(context.Background(), r, opts...)
+}
+
+// AddJSONStreamContext is like AddJSONStream but stops before the next value once
+// ctx is done, returning the report of the values processed so far and ctx.Err().
+// Cancellation is checked between values: a Read blocked on r is not interrupted.
+func (g *Generator) AddJSONStreamContext(ctx context.Context, r io.Reader, opts ...IngestOption) (IngestReport, error) {
+	cfg := newIngestConfig(opts)
+	br := bufio.NewReader(r)
+
+	arrayMode, err := startsWithArray(br)
+	if err != nil {
+		return IngestReport{}, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	var report IngestReport
+	dec := json.NewDecoder(br)
+	if g.numericPrecision {
+		dec.UseNumber()
+	}
+	fail := func(err error) (IngestReport, error) {
+		ierr := IngestError{Index: report.Accepted + 1, Offset: dec.InputOffset(), Err: err}
+		report.reject(ierr, cfg)
+		return report, &ierr
+	}
+
+	for {
+		if arrayMode {
+			tok, err := dec.Token()
+			if err == io.EOF {
+				return report, nil
+			}
+			if err != nil {
+				return fail(err)
+			}
+			if tok != json.Delim('[') {
+				return fail(fmt.Errorf("expected top-level array, got %v", tok))
+			}
+		}
+
+		for !arrayMode || dec.More() {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+
+			var data interface{}
+			if err := dec.Decode(&data); err != nil {
+				if err == io.EOF && !arrayMode {
+					return report, nil
+				}
+				return fail(err)
+			}
+			if err := g.AddParsedSample(data); err != nil {
+				return report, fmt.Errorf("offset %d: %w", dec.InputOffset(), err)
+			}
+			report.Accepted++
+		}
+
+		// Consume the closing bracket of the current top-level array.
+		if _, err := dec.Token(); err != nil {
+			return fail(err)
+		}
+	}
+}
+
+// AddSamplesContext adds the samples produced by next until it returns io.EOF or
+// ctx is done. next is called once per sample and may return any value accepted
+// by AddParsedSample; a non-nil error other than io.EOF marks that sample as
+// rejected and, unless StopOnError is given, ingestion continues.
+//
+// Cancellation is checked before every call to next. When ctx is done the report
+// of the samples processed so far is returned together with ctx.Err(). Samples
+// are added atomically, so the generator is always left in a consistent state
+// and can be used to generate a partial schema after a timeout:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+//	defer cancel()
+//	report, err := generator.AddSamplesContext(ctx, next)
+//	if errors.Is(err, context.DeadlineExceeded) {
+//	    log.Printf("schema based on the first %d samples", report.Accepted)
+//	}
+func (g *Generator) AddSamplesContext(ctx context.Context, next func() (any, error), opts ...IngestOption) (IngestReport, error) {
+	cfg := newIngestConfig(opts)
+
+	var report IngestReport
+	for index := 1; ; index++ {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		data, err := next()
+		if err == io.EOF {
+			return report, nil
+		}
+		if err != nil {
+			ierr := IngestError{Index: index, Err: err}
+			report.reject(ierr, cfg)
+			if cfg.stopOnError {
+				return report, &ierr
+			}
+			continue
+		}
+
+		if err := g.AddParsedSample(data); err != nil {
+			return report, fmt.Errorf("sample %d: %w", index, err)
+		}
+		report.Accepted++
+	}
+}
+
+// startsWithArray reports whether the first non-whitespace byte in br opens an
+// array. Whitespace is consumed; the opening byte is left in the buffer.
+func startsWithArray(br *bufio.Reader) (bool, error) {
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b == '[', br.UnreadByte()
+	}
+}
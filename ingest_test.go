@@ -0,0 +1,331 @@
+package jsonschema
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddNDJSON(t *testing.T) {
+	generator := New()
+
+	input := `{"id": 1, "name": "John"}
+{"id": 2, "name": "Jane"}
+
+{"id": 3}
+`
+	report, err := generator.AddNDJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to ingest NDJSON: %v", err)
+	}
+
+	if report.Accepted != 3 {
+		t.Errorf("Expected 3 accepted lines, got %d", report.Accepted)
+	}
+	if report.Rejected != 0 {
+		t.Errorf("Expected 0 rejected lines, got %d", report.Rejected)
+	}
+	if report.Skipped != 1 {
+		t.Errorf("Expected 1 skipped line, got %d", report.Skipped)
+	}
+
+	schema := generator.GetCurrentSchema()
+	if len(schema.Required) != 1 || schema.Required[0] != "id" {
+		t.Errorf("Expected only 'id' to be required, got %v", schema.Required)
+	}
+}
+
+func TestAddNDJSONContinueOnError(t *testing.T) {
+	generator := New()
+
+	input := "{\"a\": 1}\n{not json}\n{\"a\": 2}\n[1,\n{\"a\": 3}"
+	report, err := generator.AddNDJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error in continue mode, got %v", err)
+	}
+
+	if report.Accepted != 3 {
+		t.Errorf("Expected 3 accepted lines, got %d", report.Accepted)
+	}
+	if report.Rejected != 2 {
+		t.Fatalf("Expected 2 rejected lines, got %d", report.Rejected)
+	}
+	if report.Errors[0].Line != 2 || report.Errors[1].Line != 4 {
+		t.Errorf("Expected errors on lines 2 and 4, got %d and %d", report.Errors[0].Line, report.Errors[1].Line)
+	}
+	if report.Errors[0].Offset != 9 {
+		t.Errorf("Expected first error at offset 9, got %d", report.Errors[0].Offset)
+	}
+}
+
+func TestAddNDJSONStopOnError(t *testing.T) {
+	generator := New()
+
+	input := "{\"a\": 1}\n{not json}\n{\"a\": 2}\n"
+	report, err := generator.AddNDJSON(strings.NewReader(input), StopOnError())
+	if err == nil {
+		t.Fatal("Expected an error in stop mode")
+	}
+
+	var ierr *IngestError
+	if !errors.As(err, &ierr) || ierr.Line != 2 {
+		t.Errorf("Expected IngestError on line 2, got %v", err)
+	}
+	if report.Accepted != 1 || report.Rejected != 1 {
+		t.Errorf("Expected 1 accepted and 1 rejected, got %d and %d", report.Accepted, report.Rejected)
+	}
+}
+
+func TestAddNDJSONLongLines(t *testing.T) {
+	// Lines far larger than bufio's default buffer must be read in full.
+	long := `{"text": "` + strings.Repeat("x", 1<<20) + `"}`
+	input := long + "\n" + `{"text": "short"}` + "\n"
+
+	generator := New()
+	report, err := generator.AddNDJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to ingest NDJSON: %v", err)
+	}
+	if report.Accepted != 2 {
+		t.Errorf("Expected 2 accepted lines, got %d", report.Accepted)
+	}
+
+	generator = New()
+	report, err = generator.AddNDJSON(strings.NewReader(input), WithMaxLineSize(1024))
+	if err != nil {
+		t.Fatalf("Failed to ingest NDJSON: %v", err)
+	}
+	if report.Accepted != 1 || report.Rejected != 1 {
+		t.Fatalf("Expected 1 accepted and 1 rejected, got %d and %d", report.Accepted, report.Rejected)
+	}
+	if !errors.Is(report.Errors[0].Err, ErrLineTooLong) {
+		t.Errorf("Expected ErrLineTooLong, got %v", report.Errors[0].Err)
+	}
+}
+
+func TestAddNDJSONMaxReportedErrors(t *testing.T) {
+	generator := New()
+
+	input := "x\ny\nz\n{\"a\": 1}\r\n"
+	report, err := generator.AddNDJSON(strings.NewReader(input), WithMaxReportedErrors(2))
+	if err != nil {
+		t.Fatalf("Failed to ingest NDJSON: %v", err)
+	}
+	if report.Rejected != 3 {
+		t.Errorf("Expected 3 rejected lines, got %d", report.Rejected)
+	}
+	if len(report.Errors) != 2 {
+		t.Errorf("Expected 2 reported errors, got %d", len(report.Errors))
+	}
+	if report.Accepted != 1 {
+		t.Errorf("Expected CRLF line to be accepted, got %d accepted", report.Accepted)
+	}
+}
+
+func TestAddJSONStreamTopLevelArray(t *testing.T) {
+	generator := New()
+
+	input := `[
+  {"id": 1, "name": "John"},
+  {"id": 2}
+]`
+	report, err := generator.AddJSONStream(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to ingest stream: %v", err)
+	}
+	if report.Accepted != 2 {
+		t.Errorf("Expected 2 accepted samples, got %d", report.Accepted)
+	}
+
+	// Each element is a sample, so the root is the element schema, not an array.
+	schema := generator.GetCurrentSchema()
+	if schema.Type != "object" {
+		t.Fatalf("Expected root type object, got %v", schema.Type)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "id" {
+		t.Errorf("Expected only 'id' to be required, got %v", schema.Required)
+	}
+}
+
+func TestAddJSONStreamConsecutiveArrays(t *testing.T) {
+	generator := New()
+
+	report, err := generator.AddJSONStream(strings.NewReader(`[{"a": 1}, {"a": 2}] [{"a": 3}][]`))
+	if err != nil {
+		t.Fatalf("Failed to ingest stream: %v", err)
+	}
+	if report.Accepted != 3 {
+		t.Errorf("Expected 3 accepted samples, got %d", report.Accepted)
+	}
+}
+
+func TestAddJSONStreamConcatenated(t *testing.T) {
+	generator := New()
+
+	input := `{"a": 1}{"a": 2, "b": "x"} {"a": 3}
+[1, 2]`
+	report, err := generator.AddJSONStream(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to ingest stream: %v", err)
+	}
+	if report.Accepted != 4 {
+		t.Errorf("Expected 4 accepted samples, got %d", report.Accepted)
+	}
+
+	// A later array in a concatenated stream is a sample of its own, so "a"
+	// appears in only three of the four samples.
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["a"] == nil {
+		t.Fatal("Expected 'a' property to be defined")
+	}
+	if len(schema.Required) != 0 {
+		t.Errorf("Expected no required fields, got %v", schema.Required)
+	}
+}
+
+func TestAddJSONStreamMalformed(t *testing.T) {
+	generator := New()
+
+	report, err := generator.AddJSONStream(strings.NewReader(`[{"a": 1}, {"a": }]`))
+	var ierr *IngestError
+	if !errors.As(err, &ierr) {
+		t.Fatalf("Expected IngestError, got %v", err)
+	}
+	if report.Accepted != 1 || report.Rejected != 1 {
+		t.Errorf("Expected 1 accepted and 1 rejected, got %d and %d", report.Accepted, report.Rejected)
+	}
+
+	generator = New()
+	if _, err := generator.AddJSONStream(strings.NewReader(`[{"a": 1}`)); err == nil {
+		t.Error("Expected an error for an unterminated array")
+	}
+}
+
+func TestAddJSONStreamEmpty(t *testing.T) {
+	generator := New()
+
+	report, err := generator.AddJSONStream(strings.NewReader("  \n"))
+	if err != nil {
+		t.Fatalf("Expected no error for empty stream, got %v", err)
+	}
+	if report.Accepted != 0 {
+		t.Errorf("Expected 0 accepted samples, got %d", report.Accepted)
+	}
+}
+
+func TestAddSamplesContext(t *testing.T) {
+	generator := New()
+
+	values := []any{
+		map[string]any{"id": 1.0},
+		map[string]any{"id": 2.0, "name": "Jane"},
+	}
+	i := 0
+	next := func() (any, error) {
+		if i == len(values) {
+			return nil, io.EOF
+		}
+		i++
+		if i == 2 {
+			return nil, errors.New("bad record")
+		}
+		return values[i-1], nil
+	}
+
+	report, err := generator.AddSamplesContext(context.Background(), next)
+	if err != nil {
+		t.Fatalf("Failed to add samples: %v", err)
+	}
+	if report.Accepted != 1 || report.Rejected != 1 {
+		t.Errorf("Expected 1 accepted and 1 rejected, got %d and %d", report.Accepted, report.Rejected)
+	}
+	if report.Errors[0].Index != 2 {
+		t.Errorf("Expected rejected sample index 2, got %d", report.Errors[0].Index)
+	}
+}
+
+func TestAddSamplesContextCancel(t *testing.T) {
+	generator := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	next := func() (any, error) {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return map[string]any{"n": float64(calls)}, nil
+	}
+
+	report, err := generator.AddSamplesContext(ctx, next)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if report.Accepted != 3 {
+		t.Errorf("Expected 3 accepted samples before cancellation, got %d", report.Accepted)
+	}
+
+	// The generator must remain usable after cancellation.
+	if _, err := generator.Generate(); err != nil {
+		t.Errorf("Failed to generate partial schema: %v", err)
+	}
+}
+
+func TestAddNDJSONContextCancel(t *testing.T) {
+	generator := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := generator.AddNDJSONContext(ctx, strings.NewReader("{\"a\": 1}\n"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if report.Accepted != 0 {
+		t.Errorf("Expected no accepted lines, got %d", report.Accepted)
+	}
+}
+
+func TestAddNDJSONContextDeadline(t *testing.T) {
+	generator := New()
+
+	// An endless stream of lines: only the deadline can end ingestion.
+	r, w := io.Pipe()
+	go func() {
+		for {
+			if _, err := w.Write([]byte("{\"a\": 1}\n")); err != nil {
+				return
+			}
+		}
+	}()
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	report, err := generator.AddNDJSONContext(ctx, r)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if report.Accepted == 0 {
+		t.Error("Expected some lines to be accepted before the deadline")
+	}
+	if schema := generator.GetCurrentSchema(); schema.Properties["a"] == nil {
+		t.Error("Expected partial schema to define 'a'")
+	}
+}
+
+func TestAddJSONStreamContextCancel(t *testing.T) {
+	generator := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := generator.AddJSONStreamContext(ctx, strings.NewReader(`[{"a": 1}]`))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -5,30 +5,56 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 )
 
 // Generator generates JSON schemas from JSON samples
 type Generator struct {
-	mu            sync.Mutex
-	rootNode      *SchemaNode
-	predefined    map[string]PredefinedType
-	customFormats []CustomFormat
-	sampleCount   int
+	mu               sync.Mutex
+	rootNode         *SchemaNode
+	predefined       map[string]PredefinedType
+	predefinedPaths  []predefinedPath
+	overrides        []pathOverride
+	ignorePaths      []pathPattern
+	ignoreMode       ignoreMode
+	customFormats    []CustomFormat
+	sampleCount      int
 	maxSamples       int
 	currentSchema    *Schema
 	schemaVersion    SchemaVersion
 	examplesEnabled  bool
-	indent           string // JSON indentation string; empty = compact
+	exampleLimit     int        // WithExampleCount(n); 0 = first value only
+	rootExamples     int        // WithRootExamples; 0 = disabled
+	formatTolerance  float64    // WithFormatTolerance; 0 = strict elimination
+	formatCandidates bool       // WithFormatCandidates
+	timestamps       bool       // WithTimestampDetection
+	embeddedJSON     bool       // WithEmbeddedJSON
+	encodedContent   bool       // WithEncodedContent
+	jwtClaims        bool       // WithJWTClaims
+	patternMin       int        // WithPatternInference; 0 = disabled
+	numericPrecision bool       // WithNumericPrecision
+	defaultRatio     float64    // WithDefaultInference; 0 = disabled
+	statsEnabled     bool       // WithStats
+	statsAnnotations bool       // WithStatsAnnotations
+	redaction        bool       // WithRedaction
+	redactors        []Redactor // WithRedaction
+	redactedPaths    []string   // paths redacted in currentSchema
+	indent           string     // JSON indentation string; empty = compact
+	sampling         SamplingStrategy
+	sampler          samplerState
+	seed             uint64 // seed for randomised sampling decisions
 }
 
 // New creates a new Generator with optional configuration
 func New(opts ...Option) *Generator {
 	g := &Generator{
-		rootNode:      NewSchemaNode(),
-		predefined:    make(map[string]PredefinedType),
+		rootNode:        NewSchemaNode(),
+		predefined:      make(map[string]PredefinedType),
 		customFormats:   getBuiltInFormats(),
+		ignoreMode:      ignoreDrop,
 		schemaVersion:   Draft07, // Default to Draft 07
 		examplesEnabled: false,   // Default to disabled
 	}
@@ -44,19 +70,39 @@ func New(opts ...Option) *Generator {
 func getBuiltInFormats() []CustomFormat {
 	return []CustomFormat{
 		{Name: "date-time", Detector: isDateTime},
+		{Name: "date", Detector: isDate},
+		{Name: "time", Detector: isTime},
+		{Name: "duration", Detector: isDuration},
 		{Name: "email", Detector: isEmail},
 		{Name: "uuid", Detector: isUUID},
 		{Name: "ipv6", Detector: isIPv6},
 		{Name: "ipv4", Detector: isIPv4},
 		{Name: "uri", Detector: isURL},
+		{Name: "iri", Detector: isIRI},
+		{Name: "uri-reference", Detector: isURIReference},
+		// Templates are often valid URLs too; the more specific format wins
+		{Name: "uri-template", Detector: isURITemplate, Priority: 1},
+		{Name: "json-pointer", Detector: isJSONPointer},
+		{Name: "relative-json-pointer", Detector: isRelativeJSONPointer},
+		{Name: "regex", Detector: isRegex},
 	}
 }
 
+// HeuristicFormats are standard formats whose detectors also match ordinary
+// values, such as file names and dotted user names for "hostname", or mixed-case
+// identifiers for "byte". They are not detected by default; register them with
+// WithFormatPack(HeuristicFormats) for fields known to hold such values.
+var HeuristicFormats = []CustomFormat{
+	{Name: "hostname", Detector: isHostname},
+	{Name: "idn-hostname", Detector: isIDNHostname},
+	{Name: "byte", Detector: isBase64},
+}
+
 // AddSample adds a JSON sample to the generator and updates the schema.
 // Thread-safe: can be called concurrently from multiple goroutines.
 func (g *Generator) AddSample(jsonData string) error {
-	var data interface{}
-	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
+	data, err := g.parseSample([]byte(jsonData))
+	if err != nil {
 		return fmt.Errorf("failed to parse JSON: %w", err)
 	}
 	return g.AddParsedSample(data)
@@ -70,41 +116,149 @@ func (g *Generator) AddParsedSample(data interface{}) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	g.observeSample(data)
+	return nil
+}
+
+// observeSample records one sample in the tree. Must be called with g.mu held.
+func (g *Generator) observeSample(data interface{}) {
 	// If maxSamples is set and we've reached the limit, do nothing
 	if g.maxSamples > 0 && g.sampleCount >= g.maxSamples {
-		return nil
+		return
 	}
 
+	// Let the sampling strategy decide whether this sample is observed
+	if !g.admitSample(data) {
+		return
+	}
+
+	g.observeAdmitted(data, g.structureOnlyNext())
+}
+
+// observeAdmitted records one sample admitted by the sampling strategy;
+// structureOnly records its structure but not its values.
+// Must be called with g.mu held.
+func (g *Generator) observeAdmitted(data interface{}, structureOnly bool) {
 	g.sampleCount++
 
 	// Observe the data with the root node
-	g.rootNode.ObserveValue(data, g.examplesEnabled, g.customFormats)
-
-	// Apply predefined types to the tree
-	g.applyPredefinedTypes()
+	opts := g.observeOptions()
+	opts.structureOnly = structureOnly
+	g.observeRoot(data, &opts)
 
 	// Invalidate the cached schema; it will be rebuilt lazily on the next
 	// Generate() or GetCurrentSchema() call.  This avoids O(N) full-tree
 	// traversals while adding N samples.
 	g.currentSchema = nil
+}
 
-	return nil
+// observeRoot records one sample in the tree, and as a whole-sample example
+// with WithRootExamples. Must be called with g.mu held.
+func (g *Generator) observeRoot(data interface{}, opts *observeOptions) {
+	g.rootNode.observe(data, opts)
+	if g.rootExamples > 0 && !opts.structureOnly {
+		g.rootNode.samples.add(data, g.rootExamples)
+	}
+}
+
+// observeOptions returns the settings used to observe the next sample.
+// Must be called with g.mu held.
+func (g *Generator) observeOptions() observeOptions {
+	return observeOptions{
+		examples:         g.examplesEnabled,
+		exampleLimit:     g.exampleLimit,
+		formats:          g.customFormats,
+		formatTolerance:  g.formatTolerance,
+		timestamps:       g.timestamps,
+		embeddedJSON:     g.embeddedJSON,
+		encodedContent:   g.encodedContent,
+		jwtClaims:        g.jwtClaims,
+		patterns:         g.patternMin > 0,
+		numericPrecision: g.numericPrecision,
+		countValues:      g.defaultRatio > 0 || g.exampleLimit > 0,
+		stats:            g.statsEnabled,
+		ignore:           g.ignorePaths,
+		ignoreMode:       g.ignoreMode,
+	}
+}
+
+// renderOptions returns the settings used to turn the tree into a schema.
+// Must be called with g.mu held.
+func (g *Generator) renderOptions() renderOptions {
+	return renderOptions{
+		formatTolerance:   g.formatTolerance,
+		formatCandidates:  g.formatCandidates,
+		version:           g.schemaVersion,
+		patternMinSamples: g.patternMin,
+		numericPrecision:  g.numericPrecision,
+		defaultRatio:      g.defaultRatio,
+		exampleLimit:      g.exampleLimit,
+		statsAnnotations:  g.statsAnnotations,
+	}
+}
+
+// predefinedPath is a predefined type addressed by a path pattern.
+type predefinedPath struct {
+	pattern pathPattern
+	typ     PredefinedType
 }
 
-// applyPredefinedTypes applies predefined type configurations to nodes in the tree
-func (g *Generator) applyPredefinedTypes() {
+// pathOverride is a sub-schema override addressed by a path pattern.
+type pathOverride struct {
+	pattern  pathPattern
+	override schemaOverride
+}
+
+// applyOverrides applies predefined types and sub-schema overrides to nodes in the tree.
+// Field names given to WithPredefined address top-level properties; path patterns
+// given to WithPredefinedPath and WithOverride are matched against every node, in
+// registration order, so that a later path wins over an earlier one.
+func (g *Generator) applyOverrides() {
 	for fieldName, predefinedType := range g.predefined {
 		if node, exists := g.rootNode.objectProperties[fieldName]; exists {
 			pt := predefinedType // Create a copy
 			node.predefinedType = &pt
 		}
 	}
+
+	if len(g.predefinedPaths) == 0 && len(g.overrides) == 0 {
+		return
+	}
+	g.rootNode.walk(nil, func(path []string, node *SchemaNode) {
+		for _, pp := range g.predefinedPaths {
+			if pp.pattern.matches(path) {
+				pt := pp.typ
+				node.predefinedType = &pt
+			}
+		}
+		for i := range g.overrides {
+			if g.overrides[i].pattern.matches(path) {
+				node.override = &g.overrides[i].override
+			}
+		}
+	})
 }
 
 // buildCurrentSchema builds the current schema from the root node
 func (g *Generator) buildCurrentSchema() *Schema {
+	g.syncTree()
+
+	// Predefined types and overrides are applied here rather than after every
+	// sample, so that nodes created since the last build are covered without
+	// walking the tree once per sample.
+	g.applyOverrides()
+
 	// Use the root node's ToSchema method which handles all types
-	schema := g.rootNode.ToSchema()
+	opts := g.renderOptions()
+	schema := g.rootNode.toSchema(&opts)
+	if g.rootExamples > 0 {
+		schema.Examples = g.rootNode.samples.examples(g.rootExamples, nil)
+	}
+	if g.redaction {
+		r := redaction{redactors: g.redactors, paths: make(map[string]bool)}
+		r.redactSchema(nil, schema)
+		g.redactedPaths = r.sortedPaths()
+	}
 
 	// Add the $schema field
 	if schema.Schema == "" {
@@ -192,22 +346,49 @@ func (g *Generator) Load(schemaJSON string) error {
 	// Reset the generator
 	g.rootNode = NewSchemaNode()
 	g.currentSchema = nil
+	g.sampler = samplerState{}
 
 	// Reconstruct the tree structure from the schema
 	// We set sampleCount to 1 to represent that this schema came from at least 1 sample
 	if err := g.loadSchemaIntoNode(g.rootNode, &schema, 1); err != nil {
 		return fmt.Errorf("failed to load schema: %w", err)
 	}
+	if g.rootExamples > 0 {
+		for _, example := range schema.Examples {
+			g.rootNode.samples.add(example, g.rootExamples)
+		}
+	}
 
 	// Set the generator's sample count based on the loaded schema
-	// We use 1 as a baseline since we don't know the original count
-	g.sampleCount = 1
+	// We use 1 as a baseline unless the schema has WithStatsAnnotations counts
+	g.sampleCount = g.rootNode.sampleCount
+
+	// Reservoir sampling rebuilds the tree from retained samples; keep the loaded
+	// tree so that rebuilds start from it.
+	if g.sampling.kind == samplingReservoir {
+		g.sampler.base = g.rootNode
+	}
 
 	g.currentSchema = &schema
+	g.redactedPaths = nil
 
 	return nil
 }
 
+// RedactedPaths returns the JSON Pointers of the fields whose examples were
+// redacted in the current schema (WithRedaction), e.g. "/users/[]/email", in
+// order. Array items are written "[]".
+// Thread-safe: can be called concurrently from multiple goroutines.
+func (g *Generator) RedactedPaths() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.currentSchema == nil {
+		g.currentSchema = g.buildCurrentSchema()
+	}
+	return slices.Clone(g.redactedPaths)
+}
+
 // loadSchemaIntoNode recursively loads a schema into a node
 func (g *Generator) loadSchemaIntoNode(node *SchemaNode, schema *Schema, parentSampleCount int) error {
 	// Determine the type
@@ -223,6 +404,12 @@ func (g *Generator) loadSchemaIntoNode(node *SchemaNode, schema *Schema, parentS
 				break
 			}
 		}
+	case nil:
+		// A sub-schema without a type, such as a WithIgnoredPlaceholders {},
+		// cannot be inferred into; it is kept as is, like a replacing override.
+		node.sampleCount = parentSampleCount
+		node.override = &schemaOverride{schema: schema}
+		return nil
 	default:
 		return fmt.Errorf("unsupported type format: %T", t)
 	}
@@ -234,6 +421,12 @@ func (g *Generator) loadSchemaIntoNode(node *SchemaNode, schema *Schema, parentS
 	node.observedTypes[typeStr] = parentSampleCount
 	node.sampleCount = parentSampleCount
 
+	// Counts written by WithStatsAnnotations replace the estimates above
+	annotated := node.loadedStatsAnnotations(schema)
+	if annotated {
+		parentSampleCount = node.sampleCount
+	}
+
 	// Handle arrays
 	if typeStr == "array" && schema.Items != nil {
 		node.arrayItemNode = NewSchemaNode()
@@ -274,12 +467,101 @@ func (g *Generator) loadSchemaIntoNode(node *SchemaNode, schema *Schema, parentS
 	}
 
 	// Handle string format from loaded schema: pre-seed candidateFormats so that
-	// the loaded format survives the first round of elimination when new samples arrive.
+	// new samples keep being checked against the loaded format.
 	if typeStr == "string" && schema.Format != "" {
-		node.candidateFormats = []string{schema.Format}
-		node.candidateDetectors = []func(string) bool{func(_ string) bool { return true }}
-		node.stringCount = parentSampleCount
+		node.candidateFormats = []CustomFormat{g.loadedFormat(schema)}
+		node.stringCount = node.observedTypes["string"]
+		if g.formatTolerance > 0 {
+			// Without exact counts, earlier mismatches are not held against the format
+			matches := node.stringCount
+			if annotated {
+				mismatches, _ := extensionCount(schema, "x-format-mismatches")
+				matches = max(matches-mismatches, 0)
+			}
+			node.formatMatches = []int{matches}
+		}
+	}
+
+	// Restore embedded JSON so that new strings keep being inferred into it
+	if g.embeddedJSON && typeStr == "string" {
+		if content, ok := loadedEmbedded(schema); ok {
+			node.embedded = NewSchemaNode()
+			if err := g.loadSchemaIntoNode(node.embedded, content, parentSampleCount); err != nil {
+				return err
+			}
+		}
+	}
+
+	if g.numericPrecision {
+		node.loadedNumber(schema, typeStr)
+	}
+	switch typeStr {
+	case "string", "integer", "number", "boolean":
+		if g.defaultRatio > 0 {
+			node.loadedDefault(schema, parentSampleCount)
+		}
+		if g.exampleLimit > 0 {
+			for _, example := range schema.Examples {
+				node.samples.add(example, g.exampleLimit)
+			}
+		}
+	}
+
+	// Resume pattern inference from a pattern it inferred
+	if g.patternMin > 0 && typeStr == "string" && schema.Format == "" {
+		if template, ok := parsePatternTemplate(schema.Pattern); ok {
+			node.patternTemplate = template
+		}
+	}
+
+	// Restore the encoding so that new strings are checked against it
+	if g.encodedContent && typeStr == "string" {
+		if classes := loadedEncoding(schema); classes != 0 {
+			node.encodings, node.encodingSet = classes, true
+			if content, ok := extensionSchema(schema, "x-jwt-claims"); ok && g.jwtClaims {
+				node.jwtClaims = NewSchemaNode()
+				if err := g.loadSchemaIntoNode(node.jwtClaims, content, parentSampleCount); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Keep recorded timestamp kinds so that new values are checked against them
+	if g.timestamps {
+		if kind, ok := loadedTimestamp(schema); ok {
+			node.timestampKinds = []timestampKind{kind}
+		}
 	}
 
 	return nil
 }
+
+// loadedFormat returns the format referenced by a loaded string schema.
+// Formats known to the generator, and HeuristicFormats, keep their detector.
+// Unknown formats that come with a pattern (see WithPatternFormat) are restored
+// from it and registered, so they are also detected on new fields. Other unknown
+// formats cannot be checked and accept every value. Must be called with g.mu held.
+func (g *Generator) loadedFormat(schema *Schema) CustomFormat {
+	for _, f := range g.customFormats {
+		if f.Name == schema.Format {
+			return f
+		}
+	}
+	// Opt-in standard formats keep checking values without being registered
+	for _, f := range HeuristicFormats {
+		if f.Name == schema.Format {
+			return f
+		}
+	}
+
+	if schema.Pattern != "" {
+		if re, err := regexp.Compile(schema.Pattern); err == nil {
+			f := patternFormat(schema.Format, re)
+			g.customFormats = append(g.customFormats, f)
+			return f
+		}
+	}
+
+	return CustomFormat{Name: schema.Format, Detector: func(_ string) bool { return true }}
+}
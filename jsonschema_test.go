@@ -239,6 +239,62 @@ func TestUUIDDetection(t *testing.T) {
 	}
 }
 
+func TestDateTimeFamilyDetection(t *testing.T) {
+	generator := New()
+
+	generator.AddSample(`{"day": "2023-01-15", "at": "10:30:00Z", "local": "10:30:00", "ttl": "PT15M", "stamp": "2023-01-15T10:30:00Z", "mixed": "2023-01-15"}`)
+	generator.AddSample(`{"day": "2024-02-29", "at": "23:59:59.123+02:00", "local": "08:00:00.5", "ttl": "P3Y6M4DT12H30M5S", "stamp": "2024-02-29T08:00:00Z", "mixed": "2024-02-29T08:00:00Z"}`)
+
+	schema := generator.GetCurrentSchema()
+	expected := map[string]string{
+		"day":   "date",
+		"at":    "time",
+		"local": "time",
+		"ttl":   "duration",
+		"stamp": "date-time",
+		"mixed": "",
+	}
+	for field, format := range expected {
+		if got := schema.Properties[field].Format; got != format {
+			t.Errorf("Expected %s format to be %q, got %q", field, format, got)
+		}
+	}
+}
+
+func TestDateTimeFamilyDetectors(t *testing.T) {
+	tests := []struct {
+		detector func(string) bool
+		value    string
+		want     bool
+	}{
+		{isDate, "2023-01-15", true},
+		{isDate, "2023-02-30", false},
+		{isDate, "2023-1-15", false},
+		{isDate, "2023-01-15T10:30:00Z", false},
+		{isTime, "10:30:00", true},
+		{isTime, "10:30:00Z", true},
+		{isTime, "10:30:00.999-05:00", true},
+		{isTime, "1:30:00", false},
+		{isTime, "24:00:00", false},
+		{isTime, "10:30", false},
+		{isTime, "2023-01-15T10:30:00Z", false},
+		{isDuration, "P1D", true},
+		{isDuration, "P2W", true},
+		{isDuration, "PT0.5S", true},
+		{isDuration, "P1Y2M3DT4H5M6S", true},
+		{isDuration, "P", false},
+		{isDuration, "PT", false},
+		{isDuration, "P1DT", false},
+		{isDuration, "1D", false},
+		{isDuration, "P1H", false},
+	}
+	for _, tt := range tests {
+		if got := tt.detector(tt.value); got != tt.want {
+			t.Errorf("detector(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
 func TestIPv4Detection(t *testing.T) {
 	generator := New()
 
@@ -1165,6 +1221,82 @@ func TestCustomFormatOverride(t *testing.T) {
 	}
 }
 
+func TestPatternFormat(t *testing.T) {
+	generator := New(WithPatternFormat("order-id", `^ORD-[0-9]{8}$`))
+
+	generator.AddSample(`{"order": "ORD-12345678", "other": "ORD-1"}`)
+	generator.AddSample(`{"order": "ORD-87654321", "other": "ORD-22222222"}`)
+
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["order"].Format != "order-id" {
+		t.Errorf("Expected order-id format, got %q", schema.Properties["order"].Format)
+	}
+	if schema.Properties["order"].Pattern != `^ORD-[0-9]{8}$` {
+		t.Errorf("Expected pattern to be emitted, got %q", schema.Properties["order"].Pattern)
+	}
+	if schema.Properties["other"].Format != "" || schema.Properties["other"].Pattern != "" {
+		t.Errorf("Expected no format for other, got %+v", schema.Properties["other"])
+	}
+}
+
+func TestPatternFormatInvalidRegex(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for an invalid regular expression")
+		}
+	}()
+	WithPatternFormat("broken", `[`)
+}
+
+func TestLoadRestoresPatternFormat(t *testing.T) {
+	generator := New(WithPatternFormat("order-id", `^ORD-[0-9]{8}$`))
+	generator.AddSample(`{"order": "ORD-12345678"}`)
+	saved, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	// A fresh generator without the option restores the format from the pattern.
+	restored := New()
+	if err := restored.Load(saved); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	restored.AddSample(`{"order": "ORD-00000001", "next": "ORD-11111111"}`)
+
+	schema := restored.GetCurrentSchema()
+	if schema.Properties["order"].Format != "order-id" {
+		t.Errorf("Expected order-id format to survive Load, got %q", schema.Properties["order"].Format)
+	}
+	if schema.Properties["next"].Format != "order-id" {
+		t.Errorf("Expected restored format to be detected on new fields, got %q", schema.Properties["next"].Format)
+	}
+
+	// Values that do not match the pattern now eliminate the loaded format.
+	restored.AddSample(`{"order": "not-an-order"}`)
+	schema = restored.GetCurrentSchema()
+	if schema.Properties["order"].Format != "" {
+		t.Errorf("Expected format to be eliminated, got %q", schema.Properties["order"].Format)
+	}
+}
+
+func TestLoadUsesBuiltInFormatDetector(t *testing.T) {
+	generator := New()
+	err := generator.Load(`{"type": "object", "properties": {"email": {"type": "string", "format": "email"}}, "required": ["email"]}`)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	generator.AddSample(`{"email": "a@example.com"}`)
+	if format := generator.GetCurrentSchema().Properties["email"].Format; format != "email" {
+		t.Errorf("Expected email format, got %q", format)
+	}
+
+	generator.AddSample(`{"email": "nope"}`)
+	if format := generator.GetCurrentSchema().Properties["email"].Format; format != "" {
+		t.Errorf("Expected email format to be eliminated, got %q", format)
+	}
+}
+
 func TestExamples(t *testing.T) {
 	generator := New(WithExamples())
 
@@ -1,12 +1,16 @@
 package jsonschema
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"net"
 	"net/url"
 	"regexp"
 	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 var (
@@ -15,6 +19,9 @@ var (
 
 	// UUID pattern (supports v1-v5)
 	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[1-5][0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}$`)
+
+	// ISO 8601 duration pattern; emptiness of the components is checked separately
+	durationPattern = regexp.MustCompile(`^P(?:[0-9]+Y)?(?:[0-9]+M)?(?:[0-9]+W)?(?:[0-9]+D)?(?:T(?:[0-9]+H)?(?:[0-9]+M)?(?:[0-9]+(?:[.,][0-9]+)?S)?)?$`)
 )
 
 // SchemaNode represents a node in the schema tree
@@ -27,9 +34,58 @@ type SchemaNode struct {
 	// For primitive string values - format detection
 	// Candidates are eliminated incrementally in ObserveValue as each string arrives,
 	// so no buffering of string values is required.  Memory cost is O(1) per field.
-	stringCount        int                  // total number of string values ever observed
-	candidateFormats   []string             // format names not yet eliminated; nil = not yet initialised
-	candidateDetectors []func(string) bool  // detectors parallel to candidateFormats
+	stringCount      int            // total number of string values ever observed
+	candidateFormats []CustomFormat // formats not yet eliminated; nil = not yet initialised
+
+	// With WithFormatTolerance candidates are never eliminated; instead
+	// formatMatches counts the strings matched by each of them (same indexes).
+	formatMatches []int
+
+	// Timestamp kinds matched by every string and numeric value so far
+	// (WithTimestampDetection); nil = no value observed yet.
+	timestampKinds []timestampKind
+
+	// JSON embedded in string values (WithEmbeddedJSON): embedded observes the
+	// decoded content; embeddedOff is set once a string is not JSON.
+	embedded    *SchemaNode
+	embeddedOff bool
+
+	// Encodings shared by every string value (WithEncodedContent), and the
+	// claims of JWT values (WithJWTClaims).
+	encodings   encodingClass
+	encodingSet bool
+	jwtClaims   *SchemaNode
+
+	// Character-class template shared by every string value
+	// (WithPatternInference); patternOff is set once the values diverge.
+	patternTemplate []patternToken
+	patternOff      bool
+
+	// Numeric steps (WithNumericPrecision): GCD of the integer values and
+	// largest number of decimal places; precisionOff is set when a value has
+	// more than maxPrecision decimal places.
+	numberGCD    uint64
+	precision    int
+	precisionOff bool
+
+	// Distinct integer values seen, counted up to minStepValues; stepValues
+	// holds them until the count is reached.
+	stepDistinct int
+	stepValues   []float64
+
+	// Most frequent primitive values (WithDefaultInference, WithExampleCount(n)).
+	values valueCounter
+
+	// Diverse selection of values (WithExampleCount(n)), or of whole samples at
+	// the root (WithRootExamples).
+	samples exampleSet
+
+	// nullCount is the number of times the property was present with a null
+	// value; such occurrences are not observed (see observe).
+	nullCount int
+
+	// Value range and distinct values (WithStats).
+	stats valueStats
 
 	// Const tracking for primitive values (string, integer, number, boolean).
 	// If all observed values are identical, constValue holds that value and
@@ -38,6 +94,12 @@ type SchemaNode struct {
 	constSet    bool
 	constDiffer bool
 
+	// Number of values observed without their value being tracked
+	// (StructureOnlyAfter). Formats, defaults and the other value-derived
+	// keywords then only describe the first values, which the schema notes in
+	// x-values-sampled.
+	untracked int
+
 	// First value seen (used as example in schema)
 	firstValue interface{}
 
@@ -49,6 +111,27 @@ type SchemaNode struct {
 
 	// Predefined type override
 	predefinedType *PredefinedType
+
+	// Sub-schema override (WithOverride / WithMergedOverride)
+	override *schemaOverride
+
+	// Set when the node matches WithIgnorePaths: only its presence is counted
+	ignore ignoreMode
+}
+
+// ignoreMode tells how a node excluded with WithIgnorePaths is handled.
+type ignoreMode int
+
+const (
+	ignoreNone        ignoreMode = iota
+	ignoreDrop                   // left out of the schema entirely
+	ignorePlaceholder            // emitted as an unconstrained {} schema
+)
+
+// schemaOverride is a user-supplied sub-schema attached to a node.
+type schemaOverride struct {
+	schema *Schema
+	merge  bool // overlay onto the inferred schema instead of replacing it
 }
 
 // NewSchemaNode creates a new schema node
@@ -59,16 +142,104 @@ func NewSchemaNode() *SchemaNode {
 	}
 }
 
+// observeOptions carries the generator settings that affect how values are
+// recorded. A single instance is shared by the whole traversal of a sample.
+type observeOptions struct {
+	examples bool
+	formats  []CustomFormat
+
+	// formatTolerance enables counting format matches instead of eliminating
+	// candidates (WithFormatTolerance); 0 means strict elimination.
+	formatTolerance float64
+
+	// timestamps enables non-standard timestamp recognition (WithTimestampDetection).
+	timestamps bool
+
+	// embeddedJSON enables inference of JSON encoded in strings (WithEmbeddedJSON).
+	embeddedJSON bool
+
+	// encodedContent enables encoding detection (WithEncodedContent) and
+	// jwtClaims the inference of JWT claims (WithJWTClaims).
+	encodedContent bool
+	jwtClaims      bool
+
+	// patterns enables pattern inference (WithPatternInference).
+	patterns bool
+
+	// numericPrecision enables step tracking of numbers (WithNumericPrecision).
+	numericPrecision bool
+
+	// exampleLimit is the number of examples kept per field (WithExampleCount(n)).
+	exampleLimit int
+
+	// countValues enables value counting (WithDefaultInference, WithExampleCount(n)).
+	countValues bool
+
+	// stats enables the value statistics of WithStats.
+	stats bool
+
+	// structureOnly records types and field presence but no value-derived state
+	// (examples, const, formats). Set by StructureOnlyAfter sampling.
+	structureOnly bool
+
+	// ignore lists the path patterns whose subtrees are not observed, and
+	// ignoreMode how the nodes created for them are rendered.
+	ignore     []pathPattern
+	ignoreMode ignoreMode
+
+	// path is the path of the node being observed, maintained during traversal.
+	path []string
+}
+
+// newChild creates the node for the child segment of the node at opts.path,
+// marking it as ignored when it matches an ignore pattern.
+func (opts *observeOptions) newChild(segment string) *SchemaNode {
+	child := NewSchemaNode()
+	if len(opts.ignore) > 0 {
+		path := append(opts.path, segment)
+		for _, pattern := range opts.ignore {
+			if pattern.matches(path) {
+				child.ignore = opts.ignoreMode
+				break
+			}
+		}
+	}
+	return child
+}
+
 // ObserveValue updates this node with a new observed value.
 // formats is the list of format detectors to evaluate against string values;
 // passing the same slice on every call is fine — it is read-only here.
 func (n *SchemaNode) ObserveValue(value interface{}, examplesEnabled bool, formats []CustomFormat) {
+	n.observe(value, &observeOptions{examples: examplesEnabled, formats: formats})
+}
+
+// observe is ObserveValue with the full set of generator settings.
+func (n *SchemaNode) observe(value interface{}, opts *observeOptions) {
+	// Ignored subtrees are neither recorded nor descended into; counting the
+	// node keeps the required/optional status of its placeholder accurate.
+	if n.ignore != ignoreNone {
+		n.sampleCount++
+		return
+	}
+
+	// Numbers decoded with UseNumber keep their text for WithNumericPrecision
+	var numberText string
+	if num, ok := value.(json.Number); ok {
+		if f, err := num.Float64(); err == nil {
+			numberText, value = num.String(), f
+		}
+	}
+
 	// Capture first value as example
-	if examplesEnabled && n.sampleCount == 0 {
+	if opts.examples && !opts.structureOnly && n.sampleCount == 0 {
 		n.firstValue = value
 	}
 
 	n.sampleCount++
+	if opts.structureOnly {
+		n.untracked++
+	}
 
 	// Determine the primitive type
 	typeName := getPrimitiveType(value)
@@ -78,6 +249,10 @@ func (n *SchemaNode) ObserveValue(value interface{}, examplesEnabled bool, forma
 	// (object, array) are excluded — they cannot produce a useful const.
 	switch typeName {
 	case "string", "integer", "number", "boolean":
+		if opts.exampleLimit > 0 && !opts.structureOnly {
+			n.samples.add(value, opts.exampleLimit)
+		}
+		// Const stays exact past StructureOnlyAfter: it is a single comparison.
 		if !n.constDiffer {
 			if !n.constSet {
 				n.constValue = value
@@ -89,35 +264,68 @@ func (n *SchemaNode) ObserveValue(value interface{}, examplesEnabled bool, forma
 		}
 	}
 
+	// Timestamps may be strings or numbers (epochs)
+	switch typeName {
+	case "string", "integer", "number":
+		if opts.timestamps && !opts.structureOnly {
+			n.observeTimestamp(value)
+		}
+	}
+
+	if opts.numericPrecision && !opts.structureOnly {
+		if f, ok := value.(float64); ok {
+			n.observeNumber(f, numberText)
+		}
+	}
+	if opts.countValues && !opts.structureOnly {
+		n.observeDefault(value)
+	}
+	if opts.stats && !opts.structureOnly {
+		n.observeStats(value)
+	}
+
 	// Handle each type specifically
 	switch typeName {
 	case "string":
-		if str, ok := value.(string); ok {
+		if str, ok := value.(string); ok && !opts.structureOnly {
 			n.stringCount++
 
+			if opts.embeddedJSON {
+				n.observeEmbedded(str, opts)
+			}
+			if opts.encodedContent {
+				n.observeEncoding(str, opts)
+			}
+			if opts.patterns {
+				n.observePattern(str)
+			}
+
 			// Initialise candidate list on the very first string value.
 			if n.candidateFormats == nil {
-				n.candidateFormats = make([]string, 0, len(formats))
-				n.candidateDetectors = make([]func(string) bool, 0, len(formats))
-				for _, f := range formats {
-					n.candidateFormats = append(n.candidateFormats, f.Name)
-					n.candidateDetectors = append(n.candidateDetectors, f.Detector)
+				n.candidateFormats = append(make([]CustomFormat, 0, len(opts.formats)), opts.formats...)
+				if opts.formatTolerance > 0 {
+					n.formatMatches = make([]int, len(n.candidateFormats))
 				}
 			}
 
-			// Eliminate candidates that don't match this string.
-			// Compact in-place so we allocate nothing.
-			if len(n.candidateFormats) > 0 {
+			if n.formatMatches != nil {
+				// Tolerant detection keeps every candidate and counts its matches.
+				for i, f := range n.candidateFormats {
+					if f.Detector(str) {
+						n.formatMatches[i]++
+					}
+				}
+			} else if len(n.candidateFormats) > 0 {
+				// Eliminate candidates that don't match this string.
+				// Compact in-place so we allocate nothing.
 				j := 0
-				for i, detect := range n.candidateDetectors {
-					if detect(str) {
-						n.candidateFormats[j] = n.candidateFormats[i]
-						n.candidateDetectors[j] = n.candidateDetectors[i]
+				for _, f := range n.candidateFormats {
+					if f.Detector(str) {
+						n.candidateFormats[j] = f
 						j++
 					}
 				}
 				n.candidateFormats = n.candidateFormats[:j]
-				n.candidateDetectors = n.candidateDetectors[:j]
 			}
 		}
 
@@ -125,12 +333,14 @@ func (n *SchemaNode) ObserveValue(value interface{}, examplesEnabled bool, forma
 		if arr, ok := value.([]interface{}); ok {
 			// Ensure we have a node for array items
 			if n.arrayItemNode == nil {
-				n.arrayItemNode = NewSchemaNode()
+				n.arrayItemNode = opts.newChild(itemsSegment)
 			}
 			// Observe each item in the array
+			opts.path = append(opts.path, itemsSegment)
 			for _, item := range arr {
-				n.arrayItemNode.ObserveValue(item, examplesEnabled, formats)
+				n.arrayItemNode.observe(item, opts)
 			}
+			opts.path = opts.path[:len(opts.path)-1]
 		}
 
 	case "object":
@@ -140,25 +350,180 @@ func (n *SchemaNode) ObserveValue(value interface{}, examplesEnabled bool, forma
 			// not incremented, which makes the field optional (sampleCount < parent).
 			for key, val := range obj {
 				if n.objectProperties[key] == nil {
-					n.objectProperties[key] = NewSchemaNode()
+					n.objectProperties[key] = opts.newChild(key)
 				}
 				if val != nil {
-					n.objectProperties[key].ObserveValue(val, examplesEnabled, formats)
+					opts.path = append(opts.path, key)
+					n.objectProperties[key].observe(val, opts)
+					opts.path = opts.path[:len(opts.path)-1]
+				} else {
+					n.objectProperties[key].nullCount++
 				}
 			}
 		}
 	}
 }
 
+// merge folds the observations recorded in other into n, as if every value
+// observed by other had also been observed by n. other is left untouched and no
+// node of other is shared with n afterwards, so other may keep observing values.
+// The only order-dependent state is the example: n keeps its own first value and
+// only adopts other's when n has not observed anything yet.
+func (n *SchemaNode) merge(other *SchemaNode) {
+	if other.ignore != ignoreNone {
+		n.ignore = other.ignore
+	}
+	if n.sampleCount == 0 {
+		n.firstValue = other.firstValue
+	}
+	n.sampleCount += other.sampleCount
+	n.untracked += other.untracked
+	for typ, count := range other.observedTypes {
+		n.observedTypes[typ] += count
+	}
+
+	// Surviving formats are the intersection of both candidate lists. Both were
+	// initialised from the same ordered format list, so keeping n's order yields
+	// the same result as sequential elimination. Tolerant match counts add up.
+	n.stringCount += other.stringCount
+	if other.candidateFormats != nil {
+		switch {
+		case n.candidateFormats == nil:
+			n.candidateFormats = append([]CustomFormat{}, other.candidateFormats...)
+			if other.formatMatches != nil {
+				n.formatMatches = append([]int{}, other.formatMatches...)
+			}
+		case n.formatMatches != nil && other.formatMatches != nil:
+			for i, f := range n.candidateFormats {
+				for j, otherFormat := range other.candidateFormats {
+					if f.Name == otherFormat.Name {
+						n.formatMatches[i] += other.formatMatches[j]
+						break
+					}
+				}
+			}
+		default:
+			j := 0
+			for _, f := range n.candidateFormats {
+				for _, otherFormat := range other.candidateFormats {
+					if f.Name == otherFormat.Name {
+						n.candidateFormats[j] = f
+						j++
+						break
+					}
+				}
+			}
+			n.candidateFormats = n.candidateFormats[:j]
+		}
+	}
+
+	n.mergeTimestamps(other)
+	n.mergeEmbedded(other)
+	n.mergeEncoding(other)
+	n.mergePattern(other)
+	n.mergeNumber(other)
+	n.values.merge(&other.values)
+	n.samples.merge(&other.samples)
+	n.nullCount += other.nullCount
+	n.mergeStats(other)
+
+	switch {
+	case n.constDiffer || !other.constSet:
+		// Nothing to learn from other.
+	case other.constDiffer || (n.constSet && n.constValue != other.constValue):
+		n.constDiffer = true
+		n.constValue = nil
+	case !n.constSet:
+		n.constValue = other.constValue
+		n.constSet = true
+	}
+
+	if other.arrayItemNode != nil {
+		if n.arrayItemNode == nil {
+			n.arrayItemNode = NewSchemaNode()
+		}
+		n.arrayItemNode.merge(other.arrayItemNode)
+	}
+
+	for key, otherChild := range other.objectProperties {
+		child := n.objectProperties[key]
+		if child == nil {
+			child = NewSchemaNode()
+			n.objectProperties[key] = child
+		}
+		child.merge(otherChild)
+	}
+}
+
+// renderOptions carries the generator settings that affect how the observed
+// state of a node is turned into a schema.
+type renderOptions struct {
+	// formatTolerance is the minimum fraction of strings a format must match
+	// when match counts are kept (WithFormatTolerance).
+	formatTolerance float64
+
+	// formatCandidates lists all matching formats as x-format-candidates.
+	formatCandidates bool
+
+	// version is the JSON Schema draft of the output.
+	version SchemaVersion
+
+	// patternMinSamples is the number of strings needed before an inferred
+	// pattern is emitted (WithPatternInference); 0 disables inferred patterns.
+	patternMinSamples int
+
+	// numericPrecision emits multipleOf and x-precision (WithNumericPrecision).
+	numericPrecision bool
+
+	// defaultRatio is the share of values the most frequent one needs to be
+	// emitted as default (WithDefaultInference); 0 disables defaults.
+	defaultRatio float64
+
+	// exampleLimit is the number of examples emitted per field (WithExampleCount(n)).
+	exampleLimit int
+
+	// statsAnnotations emits the x-sample-count, x-type-counts, x-null-count
+	// and x-occurrence keywords (WithStatsAnnotations).
+	statsAnnotations bool
+}
+
 // ToSchema converts this node to a JSON Schema.
 // Format detection state is already fully up-to-date in candidateFormats — no
 // formats argument is needed here.
 func (n *SchemaNode) ToSchema() *Schema {
+	return n.toSchema(&renderOptions{})
+}
+
+// toSchema is ToSchema with the full set of generator settings.
+func (n *SchemaNode) toSchema(opts *renderOptions) *Schema {
+	// Ignored subtrees were never observed: emit an unconstrained placeholder
+	if n.ignore != ignoreNone {
+		return &Schema{}
+	}
+
+	// A replacing override short-circuits inference entirely
+	if n.override != nil && !n.override.merge {
+		return n.override.schema.clone()
+	}
+
+	schema := n.inferSchema(opts)
+	if n.override != nil {
+		schema.overlay(n.override.schema)
+	}
+	if opts.statsAnnotations {
+		n.annotateStats(schema)
+	}
+	return schema
+}
+
+// inferSchema builds the schema of this node from the observed values and its
+// predefined type, if any.
+func (n *SchemaNode) inferSchema(opts *renderOptions) *Schema {
 	schema := &Schema{}
 
 	// Handle predefined types first
 	if n.predefinedType != nil {
-		return n.applyPredefinedType()
+		return n.applyPredefinedType(opts)
 	}
 
 	// Determine the primary type
@@ -187,20 +552,39 @@ func (n *SchemaNode) ToSchema() *Schema {
 		schema.Const = n.constValue
 	}
 
+	n.applyDefault(schema, opts)
+
 	// Add example (first value observed)
 	if n.firstValue != nil {
 		schema.Example = n.firstValue
 	}
+	switch primaryType {
+	case "string", "integer", "number", "boolean":
+		if opts.exampleLimit > 0 {
+			schema.Examples = n.samples.examples(opts.exampleLimit, &n.values)
+		}
+		if n.untracked > 0 {
+			schema.setExtension("x-values-sampled", n.sampleCount-n.untracked)
+		}
+	}
 
 	// Apply type-specific logic
 	switch primaryType {
 	case "string":
-		n.applyStringPatterns(schema)
+		n.applyStringPatterns(schema, opts)
+		n.applyTimestamp(schema)
+		n.applyEmbedded(schema, opts)
+		n.applyEncoding(schema, opts)
+		n.applyPattern(schema, opts)
+
+	case "integer", "number":
+		n.applyTimestamp(schema)
+		n.applyNumber(schema, opts)
 
 	case "array":
 		schema.Type = "array"
-		if n.arrayItemNode != nil {
-			schema.Items = n.arrayItemNode.ToSchema()
+		if n.arrayItemNode != nil && n.arrayItemNode.ignore != ignoreDrop {
+			schema.Items = n.arrayItemNode.toSchema(opts)
 		}
 
 	case "object":
@@ -210,10 +594,18 @@ func (n *SchemaNode) ToSchema() *Schema {
 			required := []string{}
 
 			for key, childNode := range n.objectProperties {
-				schema.Properties[key] = childNode.ToSchema()
+				if childNode.ignore == ignoreDrop {
+					continue
+				}
+				schema.Properties[key] = childNode.toSchema(opts)
 				// A property is required if it appeared in every observation of this object
 				if childNode.sampleCount == n.sampleCount {
 					required = append(required, key)
+				} else {
+					childNode.applyOptionalDefault(schema.Properties[key], opts)
+				}
+				if opts.statsAnnotations {
+					schema.Properties[key].setExtension("x-occurrence", childNode.occurrence(n.observedTypes["object"]))
 				}
 			}
 
@@ -245,17 +637,78 @@ func (n *SchemaNode) getPrimaryType() string {
 // applyStringPatterns sets the format on the schema based on the candidates that
 // survived incremental elimination during ObserveValue calls.
 // No processing happens here — all elimination is done eagerly as strings arrive.
-func (n *SchemaNode) applyStringPatterns(schema *Schema) {
+func (n *SchemaNode) applyStringPatterns(schema *Schema, opts *renderOptions) {
 	if n.stringCount == 0 {
 		return
 	}
-	if len(n.candidateFormats) > 0 {
-		schema.Format = n.candidateFormats[0]
+	ranked := n.rankedFormats(opts)
+	if len(ranked) == 0 {
+		return
+	}
+
+	best := ranked[0]
+	setFormat(schema, n.candidateFormats[best])
+	if n.formatMatches != nil {
+		if mismatches := n.stringCount - n.formatMatches[best]; mismatches > 0 {
+			schema.setExtension("x-format-mismatches", mismatches)
+		}
+	}
+	if opts.formatCandidates && len(ranked) > 1 {
+		names := make([]string, len(ranked))
+		for i, idx := range ranked {
+			names[i] = n.candidateFormats[idx].Name
+		}
+		schema.setExtension("x-format-candidates", names)
+	}
+}
+
+// rankedFormats returns the indexes of the candidate formats that qualify for
+// the schema, most specific first: highest priority, then, with tolerant
+// detection, most matched strings, then registration order.
+// With strict elimination every surviving candidate qualifies; with tolerant
+// detection a candidate must match at least the tolerated fraction of strings.
+func (n *SchemaNode) rankedFormats(opts *renderOptions) []int {
+	threshold := opts.formatTolerance
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	var ranked []int
+	for i := range n.candidateFormats {
+		if n.formatMatches != nil {
+			matches := n.formatMatches[i]
+			if matches == 0 || float64(matches) < threshold*float64(n.stringCount) {
+				continue
+			}
+		}
+		ranked = append(ranked, i)
+	}
+
+	sort.SliceStable(ranked, func(a, b int) bool {
+		fa, fb := n.candidateFormats[ranked[a]], n.candidateFormats[ranked[b]]
+		if fa.Priority != fb.Priority {
+			return fa.Priority > fb.Priority
+		}
+		if n.formatMatches != nil {
+			return n.formatMatches[ranked[a]] > n.formatMatches[ranked[b]]
+		}
+		return false
+	})
+	return ranked
+}
+
+// setFormat sets format on the schema. Pattern formats carry their definition
+// into the schema, which both documents the format and lets Load restore its
+// detector.
+func setFormat(schema *Schema, format CustomFormat) {
+	schema.Format = format.Name
+	if format.Pattern != "" {
+		schema.Pattern = format.Pattern
 	}
 }
 
 // applyPredefinedType applies a predefined type configuration
-func (n *SchemaNode) applyPredefinedType() *Schema {
+func (n *SchemaNode) applyPredefinedType(opts *renderOptions) *Schema {
 	schema := &Schema{}
 
 	switch *n.predefinedType {
@@ -272,15 +725,18 @@ func (n *SchemaNode) applyPredefinedType() *Schema {
 		schema.Type = "integer"
 	case Array:
 		schema.Type = "array"
-		if n.arrayItemNode != nil {
-			schema.Items = n.arrayItemNode.ToSchema()
+		if n.arrayItemNode != nil && n.arrayItemNode.ignore != ignoreDrop {
+			schema.Items = n.arrayItemNode.toSchema(opts)
 		}
 	case Object:
 		schema.Type = "object"
 		if len(n.objectProperties) > 0 {
 			schema.Properties = make(map[string]*Schema)
 			for key, childNode := range n.objectProperties {
-				schema.Properties[key] = childNode.ToSchema()
+				if childNode.ignore == ignoreDrop {
+					continue
+				}
+				schema.Properties[key] = childNode.toSchema(opts)
 			}
 		}
 	}
@@ -326,6 +782,40 @@ func isDateTime(value string) bool {
 	return err == nil
 }
 
+// isDate checks if a string value is an RFC 3339 full-date ("2023-01-15").
+func isDate(value string) bool {
+	if len(value) != 10 {
+		return false
+	}
+	_, err := time.Parse(time.DateOnly, value)
+	return err == nil
+}
+
+// isTime checks if a string value is an RFC 3339 full-time ("10:30:00Z",
+// "10:30:00.5+02:00") or partial-time ("10:30:00"). Fractional seconds are
+// accepted by time.Parse even though the layouts do not mention them.
+func isTime(value string) bool {
+	// "15:04:05" is the shortest valid value; time.Parse accepts one-digit hours.
+	if len(value) < 8 || value[2] != ':' || value[5] != ':' {
+		return false
+	}
+	if _, err := time.Parse("15:04:05Z07:00", value); err == nil {
+		return true
+	}
+	_, err := time.Parse(time.TimeOnly, value)
+	return err == nil
+}
+
+// isDuration checks if a string value is an ISO 8601 duration ("P3Y6M4DT12H30M5S",
+// "PT15M", "P2W"). At least one component is required, and "T" must be
+// followed by a time component.
+func isDuration(value string) bool {
+	if len(value) < 3 || value[0] != 'P' || strings.HasSuffix(value, "T") {
+		return false
+	}
+	return durationPattern.MatchString(value)
+}
+
 // isEmail checks if a string value matches email format.
 // A cheap '@' presence check is done before the regex.
 func isEmail(value string) bool {
@@ -362,6 +852,10 @@ func isURL(value string) bool {
 	if !strings.HasPrefix(value, "http") && !strings.HasPrefix(value, "ftp") {
 		return false
 	}
+	// URIs are ASCII only; non-ASCII values are left to "iri".
+	if strings.ContainsFunc(value, isNotURIChar) {
+		return false
+	}
 	u, err := url.Parse(value)
 	if err != nil || u.Host == "" {
 		return false
@@ -372,3 +866,252 @@ func isURL(value string) bool {
 	}
 	return false
 }
+
+// isIRI checks if a string value is an absolute IRI with an authority, such as
+// "https://bücher.example/straße" or "s3://bucket/key". Non-ASCII characters are
+// allowed; IRIs made of ASCII only are usually also reported as "uri" first.
+func isIRI(value string) bool {
+	i := strings.Index(value, "://")
+	if i <= 0 || i+3 == len(value) || !isURIScheme(value[:i]) {
+		return false
+	}
+	return !strings.ContainsAny(value, uriExcluded) && !strings.ContainsFunc(value, unicode.IsSpace)
+}
+
+// uriExcluded lists ASCII characters that may not appear literally in a URI or IRI.
+const uriExcluded = "<>\"^`{|}"
+
+// isURIScheme checks if s is a valid URI scheme: a letter followed by letters,
+// digits, '+', '-' or '.'.
+func isURIScheme(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isURIReference checks if a string value is a URI reference: an absolute URI or
+// a relative reference starting with '/', "./" or "../". Other relative
+// references, such as "N/A", "a/b" or "#FF5733", are left out because too many
+// ordinary strings would qualify.
+func isURIReference(value string) bool {
+	if value == "" {
+		return false
+	}
+	switch {
+	case value[0] == '/', strings.HasPrefix(value, "./"), strings.HasPrefix(value, "../"):
+	case strings.Contains(value, "://"):
+		if !isURIScheme(value[:strings.Index(value, "://")]) {
+			return false
+		}
+	default:
+		return false
+	}
+	if strings.ContainsAny(value, uriExcluded) || strings.ContainsFunc(value, isNotURIChar) {
+		return false
+	}
+	_, err := url.Parse(value)
+	return err == nil
+}
+
+// isNotURIChar reports whether r may not appear literally in a URI.
+func isNotURIChar(r rune) bool {
+	return r <= ' ' || r >= 0x7f
+}
+
+// isURITemplate checks if a string value is a URI template (RFC 6570) with at
+// least one expression, such as "/users/{id}" or "https://api.example/{+path}{?q,page}".
+func isURITemplate(value string) bool {
+	if !strings.ContainsRune(value, '{') {
+		return false
+	}
+	for rest := value; rest != ""; {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			break
+		}
+		if rest[open] == '}' {
+			return false
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 || !isTemplateExpression(rest[open+1:open+end]) {
+			return false
+		}
+		rest = rest[open+end+1:]
+	}
+	literals := uriTemplateExpression.ReplaceAllString(value, "")
+	return !strings.ContainsAny(literals, "<>\"^`|") && !strings.ContainsFunc(literals, unicode.IsSpace)
+}
+
+// uriTemplateExpression matches the expressions of a URI template.
+var uriTemplateExpression = regexp.MustCompile(`\{[^}]*\}`)
+
+// isTemplateExpression checks the inside of a URI template expression: an
+// optional operator followed by comma-separated variables with optional
+// ":N" prefix or "*" explode modifiers.
+func isTemplateExpression(expr string) bool {
+	if expr != "" && strings.ContainsRune("+#./;?&", rune(expr[0])) {
+		expr = expr[1:]
+	}
+	for _, variable := range strings.Split(expr, ",") {
+		if i := strings.IndexByte(variable, ':'); i >= 0 {
+			if !isArrayIndex(variable[i+1:]) {
+				return false
+			}
+			variable = variable[:i]
+		} else {
+			variable = strings.TrimSuffix(variable, "*")
+		}
+		if variable == "" {
+			return false
+		}
+		for i := 0; i < len(variable); i++ {
+			c := variable[i]
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '%') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isJSONPointer checks if a string value is a non-empty JSON Pointer (RFC 6901)
+// such as "/definitions/user" or "/a~1b". '~' must be escaped as "~0" or "~1".
+func isJSONPointer(value string) bool {
+	if value == "" || value[0] != '/' {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] == '~' && (i+1 == len(value) || (value[i+1] != '0' && value[i+1] != '1')) {
+			return false
+		}
+	}
+	return true
+}
+
+// isRelativeJSONPointer checks if a string value is a relative JSON Pointer such
+// as "0/name", "1#" or "2/items/0". Bare integers, which are valid relative
+// pointers too, are left out so that numeric strings do not qualify, and so are
+// pointers whose first reference token is a number, so that dates ("2024/01/15")
+// and fractions ("3/4") do not qualify either.
+func isRelativeJSONPointer(value string) bool {
+	i := 0
+	for i < len(value) && value[i] >= '0' && value[i] <= '9' {
+		i++
+	}
+	if i == 0 || i == len(value) || (value[0] == '0' && i > 1) {
+		return false
+	}
+	if value[i:] == "#" {
+		return true
+	}
+	token, _, _ := strings.Cut(value[i+1:], "/")
+	return token != "" && strings.Trim(token, "0123456789") != "" && isJSONPointer(value[i:])
+}
+
+// isHostname checks if a string value is a fully qualified host name
+// (RFC 1123) such as "api.example.com": dot-separated labels of letters, digits
+// and hyphens, ending with an alphabetic top-level label so that IPv4 addresses
+// and version numbers do not qualify.
+func isHostname(value string) bool {
+	if len(value) > 253 || !strings.ContainsRune(value, '.') {
+		return false
+	}
+	return checkHostname(value, func(r rune) bool {
+		return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+}
+
+// isIDNHostname checks if a string value is an internationalized host name such
+// as "bücher.example": like isHostname, with letters and digits from any script.
+// ASCII host names qualify too but are reported as "hostname" first.
+func isIDNHostname(value string) bool {
+	if len(value) > 1024 || !strings.ContainsRune(value, '.') {
+		return false
+	}
+	return checkHostname(value, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+	})
+}
+
+// checkHostname checks the labels of a host name, with alnum telling which
+// characters besides '-' are allowed.
+func checkHostname(value string, alnum func(rune) bool) bool {
+	labels := strings.Split(strings.TrimSuffix(value, "."), ".")
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if r != '-' && !alnum(r) {
+				return false
+			}
+		}
+	}
+	tld := []rune(labels[len(labels)-1])
+	if len(tld) < 2 {
+		return false
+	}
+	for _, r := range tld {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isRegex checks if a string value looks like a regular expression: it must be
+// anchored with a leading '^' or a trailing '$', have both anchors or another
+// metacharacter, and compile. Any string is a valid regular expression, so
+// unanchored ones cannot be told from plain text, and a single anchor alone
+// would make prices such as "5$" regexes.
+func isRegex(value string) bool {
+	if len(value) < 2 {
+		return false
+	}
+	start, end := value[0] == '^', value[len(value)-1] == '$'
+	if !start && !end {
+		return false
+	}
+	if !(start && end) && !strings.ContainsAny(strings.TrimSuffix(strings.TrimPrefix(value, "^"), "$"), `.*+?()[]{}|\`) {
+		return false
+	}
+	_, err := regexp.Compile(value)
+	return err == nil
+}
+
+// isBase64 checks if a string value looks like standard base64 data ("byte"):
+// padded to a multiple of 4, at least 12 characters, and mixing upper-case
+// letters, lower-case letters and digits or symbols, which rules out words,
+// numbers and hex digests.
+func isBase64(value string) bool {
+	if len(value) < 12 || len(value)%4 != 0 {
+		return false
+	}
+	var upper, lower, other bool
+	for i, c := range []byte(value) {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= '0' && c <= '9', c == '+', c == '/':
+			other = true
+		case c == '=' && i >= len(value)-2:
+			other = true
+		default:
+			return false
+		}
+	}
+	if !upper || !lower || !other {
+		return false
+	}
+	_, err := base64.StdEncoding.DecodeString(value)
+	return err == nil
+}
@@ -0,0 +1,155 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxPrecision is the largest number of decimal places tracked by
+// WithNumericPrecision; values with more are measurements, not steps.
+const maxPrecision = 12
+
+// minStepValues is the number of distinct integer values a field needs before
+// their GCD is emitted as multipleOf: a few values, such as 30 and 40, share a
+// step by chance.
+const minStepValues = 5
+
+// parseSample parses one JSON document. With WithNumericPrecision numbers are
+// decoded as json.Number so that their decimal places survive parsing.
+func (g *Generator) parseSample(data []byte) (interface{}, error) {
+	var sample interface{}
+	if !g.numericPrecision || !json.Valid(data) {
+		// json.Unmarshal also reports why invalid data is rejected
+		err := json.Unmarshal(data, &sample)
+		return sample, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err := dec.Decode(&sample)
+	return sample, err
+}
+
+// decimalPlaces returns the number of decimal places written in text, a JSON
+// number such as "19.90" (2) or "1.5e-3" (4).
+func decimalPlaces(text string) (int, bool) {
+	mantissa, exponent, _ := strings.Cut(strings.ToLower(text), "e")
+	places := 0
+	if _, fraction, ok := strings.Cut(mantissa, "."); ok {
+		places = len(fraction)
+	}
+	if exponent != "" {
+		e, err := strconv.Atoi(exponent)
+		if err != nil {
+			return 0, false
+		}
+		places -= e
+	}
+	return max(places, 0), true
+}
+
+// observeNumber records the step of a numeric value. text is its JSON text, or
+// empty when the value was not decoded as a json.Number, in which case the
+// shortest representation of value is used and trailing zeros are lost.
+func (n *SchemaNode) observeNumber(value float64, text string) {
+	if text == "" {
+		text = strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	if places, ok := decimalPlaces(text); !ok || places > maxPrecision {
+		n.precisionOff = true
+	} else {
+		n.precision = max(n.precision, places)
+	}
+
+	if value != math.Trunc(value) {
+		return
+	}
+	// Integers too large for the GCD only have the trivial step
+	step := uint64(1)
+	if abs := math.Abs(value); abs < 1<<63 {
+		step = uint64(abs)
+	}
+	n.numberGCD = gcd(n.numberGCD, step)
+	n.addStepValue(value)
+}
+
+// addStepValue counts value as a distinct integer value, up to minStepValues.
+func (n *SchemaNode) addStepValue(value float64) {
+	if n.stepDistinct >= minStepValues || slices.Contains(n.stepValues, value) {
+		return
+	}
+	n.stepValues = append(n.stepValues, value)
+	n.stepDistinct++
+	if n.stepDistinct == minStepValues {
+		n.stepValues = nil
+	}
+}
+
+// gcd returns the greatest common divisor of a and b; gcd(0, b) is b.
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// mergeNumber folds the numeric steps of other into n.
+func (n *SchemaNode) mergeNumber(other *SchemaNode) {
+	n.numberGCD = gcd(n.numberGCD, other.numberGCD)
+	n.precision = max(n.precision, other.precision)
+	n.precisionOff = n.precisionOff || other.precisionOff
+	if other.stepDistinct >= minStepValues {
+		n.stepDistinct, n.stepValues = minStepValues, nil
+	}
+	for _, value := range other.stepValues {
+		n.addStepValue(value)
+	}
+}
+
+// applyNumber emits multipleOf for numeric fields. Decimal fields get a step of
+// one unit of their largest number of decimal places, plus x-precision;
+// integer fields get the GCD of their values when it is larger than 1 and
+// they hold at least minStepValues distinct values.
+func (n *SchemaNode) applyNumber(schema *Schema, opts *renderOptions) {
+	if !opts.numericPrecision || schema.Const != nil {
+		return
+	}
+	if n.observedTypes["number"] > 0 {
+		if n.precisionOff || n.precision == 0 {
+			return
+		}
+		step, _ := strconv.ParseFloat("1e-"+strconv.Itoa(n.precision), 64)
+		schema.MultipleOf = &step
+		schema.setExtension("x-precision", n.precision)
+		return
+	}
+	if n.numberGCD > 1 && n.stepDistinct >= minStepValues {
+		step := float64(n.numberGCD)
+		schema.MultipleOf = &step
+	}
+}
+
+// loadedNumber restores the numeric steps of a loaded schema. Steps that the
+// schema does not state are assumed trivial, so that no multipleOf is emitted
+// that the earlier values might contradict.
+func (n *SchemaNode) loadedNumber(schema *Schema, typeStr string) {
+	switch typeStr {
+	case "integer":
+		n.numberGCD = 1
+		if schema.MultipleOf != nil && *schema.MultipleOf >= 1 && *schema.MultipleOf == math.Trunc(*schema.MultipleOf) && *schema.MultipleOf < 1<<63 {
+			n.numberGCD = uint64(*schema.MultipleOf)
+			n.stepDistinct = minStepValues
+		}
+	case "number":
+		n.numberGCD = 1
+		precision, ok := schema.Extensions["x-precision"].(float64)
+		if !ok || precision < 0 || precision > maxPrecision || precision != math.Trunc(precision) {
+			n.precisionOff = true
+			return
+		}
+		n.precision = int(precision)
+	}
+}
@@ -0,0 +1,193 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNumericPrecision(t *testing.T) {
+	generator := New(WithNumericPrecision())
+	generator.AddSample(`{"price": 19.90, "qty": 15, "rate": 0.125, "reading": 1.5e-3, "count": 7, "id": 3}`)
+	generator.AddSample(`{"price": 5, "qty": 40, "rate": 12.5, "reading": 2.25, "count": 10, "id": 3}`)
+	generator.AddSample(`{"price": 3.5, "qty": 25, "rate": 1, "reading": 0.5, "count": 13, "id": 3}`)
+	generator.AddSample(`{"price": 2.25, "qty": 10, "rate": 0.5, "reading": 0.75, "count": 16, "id": 3}`)
+	generator.AddSample(`{"price": 8, "qty": 35, "rate": 2, "reading": 1.25, "count": 19, "id": 3}`)
+
+	schema := generator.GetCurrentSchema()
+	tests := []struct {
+		field     string
+		step      float64
+		precision any
+	}{
+		{"price", 0.01, 2},
+		{"qty", 5, nil},
+		{"rate", 0.001, 3},
+		{"reading", 0.0001, 4},
+		{"count", 0, nil},
+		{"id", 0, nil},
+	}
+	for _, tt := range tests {
+		prop := schema.Properties[tt.field]
+		if tt.step == 0 {
+			if prop.MultipleOf != nil {
+				t.Errorf("%s: expected no multipleOf, got %v", tt.field, *prop.MultipleOf)
+			}
+		} else if prop.MultipleOf == nil || *prop.MultipleOf != tt.step {
+			t.Errorf("%s: expected multipleOf %v, got %v", tt.field, tt.step, prop.MultipleOf)
+		}
+		if got := prop.Extensions["x-precision"]; got != tt.precision {
+			t.Errorf("%s: expected x-precision %v, got %v", tt.field, tt.precision, got)
+		}
+	}
+
+	schemaJSON, _ := generator.Generate()
+	if !strings.Contains(schemaJSON, `"reading":{"type":"number","multipleOf":0.0001,"x-precision":4}`) {
+		t.Errorf("Unexpected reading schema in %s", schemaJSON)
+	}
+}
+
+func TestNumericPrecisionDisabled(t *testing.T) {
+	generator := New()
+	generator.AddSample(`{"price": 19.99, "qty": 10}`)
+	generator.AddSample(`{"price": 5.25, "qty": 20}`)
+
+	schema := generator.GetCurrentSchema()
+	for _, field := range []string{"price", "qty"} {
+		if prop := schema.Properties[field]; prop.MultipleOf != nil || prop.Extensions != nil {
+			t.Errorf("%s: expected no precision without WithNumericPrecision, got %+v", field, prop)
+		}
+	}
+}
+
+func TestNumericPrecisionTooPrecise(t *testing.T) {
+	generator := New(WithNumericPrecision())
+	generator.AddSample(`{"value": 0.1}`)
+	generator.AddSample(`{"value": 0.30000000000000004}`)
+
+	if prop := generator.GetCurrentSchema().Properties["value"]; prop.MultipleOf != nil || prop.Extensions != nil {
+		t.Errorf("Expected no precision for measurements, got %+v", prop)
+	}
+}
+
+func TestNumericPrecisionParsedSamples(t *testing.T) {
+	generator := New(WithNumericPrecision(), WithExamples())
+	dec := json.NewDecoder(strings.NewReader(`{"price": 1.50} {"price": 2.75}`))
+	dec.UseNumber()
+	for dec.More() {
+		var sample interface{}
+		if err := dec.Decode(&sample); err != nil {
+			t.Fatal(err)
+		}
+		if err := generator.AddParsedSample(sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+	generator.AddParsedSample(map[string]interface{}{"price": 3.5})
+
+	schema := generator.GetCurrentSchema()
+	price := schema.Properties["price"]
+	if price.Extensions["x-precision"] != 2 {
+		t.Errorf("Expected 2 decimal places, got %+v", price)
+	}
+	if price.Example != 1.5 {
+		t.Errorf("Expected float64 example, got %#v", price.Example)
+	}
+}
+
+func TestNumericPrecisionIngest(t *testing.T) {
+	generator := New(WithNumericPrecision())
+	if _, err := generator.AddNDJSON(strings.NewReader("{\"a\": 1.10}\n{\"a\": 2.20}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generator.AddJSONStream(strings.NewReader(`[{"a": 3.30}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := generator.GetCurrentSchema().Properties["a"].Extensions["x-precision"]; got != 2 {
+		t.Errorf("Expected x-precision 2 from streamed text, got %v", got)
+	}
+	if err := generator.AddSample(`{"a": 1} x`); err == nil {
+		t.Error("Expected trailing data to be rejected")
+	}
+}
+
+func TestNumericPrecisionLoad(t *testing.T) {
+	generator := New(WithNumericPrecision())
+	for qty := 10; qty <= 50; qty += 10 {
+		generator.AddSample(fmt.Sprintf(`{"price": 1.5, "qty": %d}`, qty))
+	}
+	generator.AddSample(`{"price": 2.5, "qty": 20}`)
+	saved, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	restored := New(WithNumericPrecision())
+	if err := restored.Load(saved); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	restored.AddSample(`{"price": 3.25, "qty": 25}`)
+	restored.AddSample(`{"price": 4.5, "qty": 35}`)
+
+	schema := restored.GetCurrentSchema()
+	if price := schema.Properties["price"]; price.MultipleOf == nil || *price.MultipleOf != 0.01 {
+		t.Errorf("Expected price multipleOf 0.01, got %+v", price)
+	}
+	if qty := schema.Properties["qty"]; qty.MultipleOf == nil || *qty.MultipleOf != 5 {
+		t.Errorf("Expected qty multipleOf 5, got %+v", qty)
+	}
+}
+
+func TestNumericPrecisionParallel(t *testing.T) {
+	samples := []string{
+		`{"price": 19.90, "qty": 12}`,
+		`{"price": 5.5, "qty": 18}`,
+		`{"price": 7, "qty": 30}`,
+		`{"price": 1.25, "qty": 42}`,
+		`{"price": 3, "qty": 6}`,
+	}
+	sequential := New(WithNumericPrecision())
+	parallel := NewParallel(3, WithNumericPrecision())
+	for i := 0; i < 30; i++ {
+		sequential.AddSample(samples[i%len(samples)])
+		parallel.AddSample(samples[i%len(samples)])
+	}
+
+	want, _ := sequential.Generate()
+	got, err := parallel.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if got != want {
+		t.Errorf("Parallel schema differs from sequential\ngot:  %s\nwant: %s", got, want)
+	}
+	if !strings.Contains(got, `"multipleOf":6`) {
+		t.Errorf("Expected qty multipleOf 6, got %s", got)
+	}
+}
+
+func TestNumericPrecisionIntegerStepThreshold(t *testing.T) {
+	tests := []struct {
+		ages []int
+		step float64
+	}{
+		{[]int{30, 40}, 0},
+		{[]int{30, 40, 30, 40, 30, 40}, 0},
+		{[]int{10, 20, 30, 40}, 0},
+		{[]int{10, 20, 30, 40, 50}, 10},
+	}
+	for _, tt := range tests {
+		generator := New(WithNumericPrecision())
+		for _, age := range tt.ages {
+			generator.AddSample(fmt.Sprintf(`{"age": %d}`, age))
+		}
+		got := generator.GetCurrentSchema().Properties["age"].MultipleOf
+		switch {
+		case tt.step == 0 && got != nil:
+			t.Errorf("%v: expected no multipleOf, got %v", tt.ages, *got)
+		case tt.step != 0 && (got == nil || *got != tt.step):
+			t.Errorf("%v: expected multipleOf %v, got %v", tt.ages, tt.step, got)
+		}
+	}
+}
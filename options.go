@@ -1,5 +1,7 @@
 package jsonschema
 
+import "regexp"
+
 // Option is a functional option for configuring the Generator
 type Option func(*Generator)
 
@@ -11,6 +13,10 @@ const (
 	Draft06 SchemaVersion = "http://json-schema.org/draft-06/schema#"
 	// Draft07 represents JSON Schema Draft 07 (default)
 	Draft07 SchemaVersion = "http://json-schema.org/draft-07/schema#"
+	// Draft201909 represents JSON Schema Draft 2019-09
+	Draft201909 SchemaVersion = "https://json-schema.org/draft/2019-09/schema"
+	// Draft202012 represents JSON Schema Draft 2020-12
+	Draft202012 SchemaVersion = "https://json-schema.org/draft/2020-12/schema"
 )
 
 // FormatDetector is a function that checks if a string matches a custom format
@@ -20,6 +26,14 @@ type FormatDetector func(string) bool
 type CustomFormat struct {
 	Name     string
 	Detector FormatDetector
+	// Pattern is the regular expression of formats declared with
+	// WithPatternFormat; empty for formats backed by Go code.
+	Pattern string
+	// Priority ranks formats that match the same values: the surviving format
+	// with the highest priority is emitted, and registration order breaks ties.
+	// Built-in formats have priority 0, so a stricter custom format registered
+	// with a positive priority wins over the built-in it refines.
+	Priority int
 }
 
 // PredefinedType represents a predefined type for a field
@@ -45,6 +59,69 @@ func WithPredefined(fieldName string, typeValue PredefinedType) Option {
 	}
 }
 
+// WithPredefinedPath sets a predefined type for every node matching path,
+// anywhere in the tree. path is a JSON Pointer ("/user/address/zip",
+// "/items/*/sku") or a dotted path ("user.address.zip", "items[].sku");
+// "*" matches any single property or array items and "**" any number of levels.
+// Unlike WithPredefined, dots in path are separators, not part of a field name.
+func WithPredefinedPath(path string, typeValue PredefinedType) Option {
+	return func(g *Generator) {
+		g.predefinedPaths = append(g.predefinedPaths, predefinedPath{
+			pattern: parsePath(path),
+			typ:     typeValue,
+		})
+	}
+}
+
+// WithOverride hard-codes the schema of every node matching path, replacing
+// whatever was inferred for it. Any keyword can be set this way (format, enum,
+// pattern, description, $ref...). path uses the same syntax as
+// WithPredefinedPath. The schema is copied when the generator builds its output,
+// so it must not be modified afterwards.
+func WithOverride(path string, schema *Schema) Option {
+	return withOverride(path, schema, false)
+}
+
+// WithMergedOverride is like WithOverride but combines schema with what was
+// inferred: keywords set in schema win, properties and items are merged
+// recursively and required lists are combined. Setting a type different from
+// the inferred one discards the inferred keywords.
+func WithMergedOverride(path string, schema *Schema) Option {
+	return withOverride(path, schema, true)
+}
+
+// withOverride registers a sub-schema override for path.
+func withOverride(path string, schema *Schema, merge bool) Option {
+	return func(g *Generator) {
+		g.overrides = append(g.overrides, pathOverride{
+			pattern:  parsePath(path),
+			override: schemaOverride{schema: schema, merge: merge},
+		})
+	}
+}
+
+// WithIgnorePaths excludes the subtrees matching any of patterns from
+// inference: their values are not recorded or descended into, which saves
+// memory, and they are left out of the schema. Patterns use the same syntax as
+// WithPredefinedPath; "**" is handy for fields at any depth, e.g.
+// WithIgnorePaths("debug", "**._metadata", "/events/*/trace").
+func WithIgnorePaths(patterns ...string) Option {
+	return func(g *Generator) {
+		for _, pattern := range patterns {
+			g.ignorePaths = append(g.ignorePaths, parsePath(pattern))
+		}
+	}
+}
+
+// WithIgnoredPlaceholders keeps fields excluded by WithIgnorePaths in the schema
+// as unconstrained {} placeholders, so they still appear in properties and
+// required, instead of removing them.
+func WithIgnoredPlaceholders() Option {
+	return func(g *Generator) {
+		g.ignoreMode = ignorePlaceholder
+	}
+}
+
 // WithMaxSamples sets the maximum number of samples to process
 // Once this limit is reached, AddSample will return nil but do nothing
 func WithMaxSamples(max int) Option {
@@ -53,8 +130,28 @@ func WithMaxSamples(max int) Option {
 	}
 }
 
+// WithSampling selects the sampling strategy used to decide which samples are
+// observed. By default every sample is observed (up to WithMaxSamples).
+// See Reservoir, EveryKth, TimeBucketed and StructureOnlyAfter.
+func WithSampling(strategy SamplingStrategy) Option {
+	return func(g *Generator) {
+		g.sampling = strategy
+	}
+}
+
+// WithSamplingSeed sets the seed of the random source used by randomised
+// sampling strategies such as Reservoir. The default seed is fixed, so
+// repeated runs over the same input produce the same schema.
+func WithSamplingSeed(seed uint64) Option {
+	return func(g *Generator) {
+		g.seed = seed
+	}
+}
+
 // WithCustomFormat registers a custom format detector
-// Custom formats are checked after built-in formats (date-time, email, uuid, ipv6, ipv4, uri)
+// Custom formats are checked after built-in formats (date-time, email, uuid, uri...)
+// and, having the same priority, lose to a built-in format matching the same values;
+// use WithFormat to give them a priority.
 // The formatName will be used as the value for the "format" field in the schema
 func WithCustomFormat(formatName string, detector FormatDetector) Option {
 	return func(g *Generator) {
@@ -65,6 +162,177 @@ func WithCustomFormat(formatName string, detector FormatDetector) Option {
 	}
 }
 
+// WithFormat registers a custom format given in full, e.g. with a priority:
+//
+//	WithFormat(CustomFormat{Name: "corp-email", Detector: isCorpEmail, Priority: 10})
+//
+// A format with a Pattern and no Detector is detected with the pattern, like
+// WithPatternFormat, and panics if the pattern does not compile.
+func WithFormat(format CustomFormat) Option {
+	if format.Detector == nil && format.Pattern != "" {
+		format.Detector = regexp.MustCompile(format.Pattern).MatchString
+	}
+	return func(g *Generator) {
+		g.customFormats = append(g.customFormats, format)
+	}
+}
+
+// WithTimestampDetection recognises timestamps that are not RFC 3339 date-times
+// and annotates their fields so that downstream loaders know how to parse them:
+//
+//   - strings in a common layout, such as "2006-01-02 15:04:05", RFC 1123 or
+//     the Apache log format, get "x-timestamp-layout" set to the Go time layout;
+//   - integers, numbers and numeric strings that are Unix epochs between the
+//     years 2000 and 2100 get "x-epoch-unit" set to "s", "ms", "us" or "ns".
+//
+// Like formats, an annotation is only emitted when every value of the field matches.
+func WithTimestampDetection() Option {
+	return func(g *Generator) {
+		g.timestamps = true
+	}
+}
+
+// WithFormatPack registers a set of formats, such as formats.Common from the
+// formats subpackage, as if each was passed to WithFormat.
+func WithFormatPack(pack []CustomFormat) Option {
+	opts := make([]Option, len(pack))
+	for i, format := range pack {
+		opts[i] = WithFormat(format)
+	}
+	return func(g *Generator) {
+		for _, opt := range opts {
+			opt(g)
+		}
+	}
+}
+
+// WithEmbeddedJSON detects string fields whose values all parse as JSON objects
+// or arrays, such as "{\"a\":1}", and infers a schema for their content. The
+// field gets "contentMediaType": "application/json" and the inferred schema as
+// "contentSchema" with Draft201909 and later, or as "x-contentSchema" with
+// older drafts, which have no contentSchema keyword.
+// Path options (WithIgnorePaths, WithOverride...) address the embedded content
+// as if it was not encoded: "payload.a" is property "a" of the JSON in "payload".
+func WithEmbeddedJSON() Option {
+	return func(g *Generator) {
+		g.embeddedJSON = true
+	}
+}
+
+// WithEncodedContent detects string fields holding encoded data and describes
+// them so that consumers know they are opaque values rather than text:
+//
+//   - JWTs get "contentMediaType": "application/jwt";
+//   - gzip data in base64 gets "contentEncoding": "base64" and
+//     "contentMediaType": "application/gzip";
+//   - hex strings get "contentEncoding": "base16", plus "x-digest" set to "md5",
+//     "sha1" or "sha256" when their length is that of the digest;
+//   - other base64 data gets "contentEncoding" "base64" or "base64url".
+//
+// Every value of the field must share the encoding. Values shorter than 16
+// characters are never considered encoded.
+func WithEncodedContent() Option {
+	return func(g *Generator) {
+		g.encodedContent = true
+	}
+}
+
+// WithJWTClaims enables WithEncodedContent and also infers the schema of the
+// claims of JWT fields, emitted as "x-jwt-claims". Signatures are not verified.
+func WithJWTClaims() Option {
+	return func(g *Generator) {
+		g.encodedContent = true
+		g.jwtClaims = true
+	}
+}
+
+// WithPatternInference learns a regular expression for string fields holding
+// structured identifiers that match no format, such as "AB-1234-X", and emits
+// it as "pattern": ^[A-Z]{2}-[0-9]{4}-[A-Z]$. Values are split into runs of
+// letters and digits and separator characters; runs in the same position are
+// widened to cover every value. A field stops being inferred as soon as a value
+// has a different structure, non-ASCII characters or more than 16 runs and
+// separators, so free text never gets a pattern, and memory stays bounded.
+// The pattern is only emitted after minSamples strings, and when it describes
+// more than plain words: a separator, mixed letters and digits, or fixed lengths.
+func WithPatternInference(minSamples int) Option {
+	return func(g *Generator) {
+		if minSamples < 1 {
+			minSamples = 1
+		}
+		g.patternMin = minSamples
+	}
+}
+
+// WithNumericPrecision detects the step of numeric fields and emits it as
+// "multipleOf". Decimal fields such as prices get one unit of their largest
+// number of decimal places, counted on the JSON text so that 19.90 has two, and
+// an "x-precision" hint with that number: {"multipleOf": 0.01, "x-precision": 2}.
+// Integer fields get the greatest common divisor of their values when it is
+// larger than 1 and they hold at least 5 distinct values, e.g. 5 for 15, 25,
+// 40, 10 and 35; fewer values, such as 30 and 40, share a step by chance.
+// Fields with more than 12 decimal places are left alone. AddParsedSample
+// values keep their text only when they are json.Number, as produced by
+// json.Decoder.UseNumber.
+func WithNumericPrecision() Option {
+	return func(g *Generator) {
+		g.numericPrecision = true
+	}
+}
+
+// WithDefaultInference emits the most frequent value of a field as "default"
+// when it accounts for at least ratio of its values, e.g. 0.95 for a currency
+// that is "EUR" in 97% of the records. Optional fields also get their majority
+// value (more than half of the values) as default, since that is most likely
+// what an absent field stands for. Fields that always hold the same value get
+// a const instead. Only the 16 most frequent values of each field are counted,
+// so memory stays bounded on high-cardinality fields. A ratio of 0 or less
+// disables defaults; a ratio above 1 is treated as 1.
+func WithDefaultInference(ratio float64) Option {
+	return func(g *Generator) {
+		g.defaultRatio = min(max(ratio, 0), 1)
+	}
+}
+
+// WithFormatCandidates lists every format that matched a field's values, most
+// specific first, as "x-format-candidates" when more than one did. The first
+// one is the emitted format.
+func WithFormatCandidates() Option {
+	return func(g *Generator) {
+		g.formatCandidates = true
+	}
+}
+
+// WithPatternFormat registers a custom format whose values match the regular
+// expression regex (Go RE2 syntax), without writing a FormatDetector:
+//
+//	WithPatternFormat("order-id", `^ORD-[0-9]{8}$`)
+//
+// Remember to anchor the expression: unanchored expressions match substrings.
+// When the format is detected, the schema also carries the expression as
+// "pattern", so Load can restore the format from a saved schema.
+// It panics if regex does not compile, like regexp.MustCompile; configuration
+// files read with NewFromConfig are validated and report an error instead.
+func WithPatternFormat(formatName, regex string) Option {
+	return withPatternFormat(formatName, regexp.MustCompile(regex))
+}
+
+// withPatternFormat registers a pattern format from an already compiled expression.
+func withPatternFormat(formatName string, re *regexp.Regexp) Option {
+	return func(g *Generator) {
+		g.customFormats = append(g.customFormats, patternFormat(formatName, re))
+	}
+}
+
+// patternFormat returns the CustomFormat matching values against re.
+func patternFormat(name string, re *regexp.Regexp) CustomFormat {
+	return CustomFormat{
+		Name:     name,
+		Detector: re.MatchString,
+		Pattern:  re.String(),
+	}
+}
+
 // WithoutBuiltInFormats disables all built-in format detectors
 // Use this if you want to provide your own complete set of format detectors
 func WithoutBuiltInFormats() Option {
@@ -73,6 +341,23 @@ func WithoutBuiltInFormats() Option {
 	}
 }
 
+// WithFormatTolerance makes format detection tolerate a few non-matching
+// values. By default a single string that does not match a format rules it out
+// for good; with a tolerance, the generator counts the strings each format
+// matches and emits the format matched by the most strings as long as it
+// matched at least ratio of them, e.g. 0.999. The number of strings it did not
+// match is reported as "x-format-mismatches" so the outliers can be looked into.
+// Every detector then runs on every string. A ratio of 0 or less, or of 1 or
+// more, keeps the default strict elimination.
+func WithFormatTolerance(ratio float64) Option {
+	return func(g *Generator) {
+		if ratio <= 0 || ratio >= 1 {
+			ratio = 0
+		}
+		g.formatTolerance = ratio
+	}
+}
+
 // WithSchemaVersion sets the JSON Schema draft version
 // Defaults to Draft07 if not specified
 func WithSchemaVersion(version SchemaVersion) Option {
@@ -89,6 +374,72 @@ func WithExamples() Option {
 	}
 }
 
+// WithExampleCount emits up to n distinct values of each primitive field as a
+// standard "examples" array instead of the first value as "example". They are
+// chosen to be representative rather than merely first: the most frequent
+// value, the shortest and longest (or smallest and largest), then a random
+// sample of the distinct values. The sample is picked by hash, so it does not
+// depend on the order of the samples and only n values are kept. An n of 0 or
+// less disables it.
+func WithExampleCount(n int) Option {
+	return func(g *Generator) {
+		g.exampleLimit = max(n, 0)
+	}
+}
+
+// WithStats records the value statistics reported by Stats: the range of
+// numbers, the length range of strings and an estimate of the number of
+// distinct values of every field. The estimate uses a k-minimum-values sketch
+// of 64 hashes per field, so memory stays bounded whatever the cardinality.
+func WithStats() Option {
+	return func(g *Generator) {
+		g.statsEnabled = true
+	}
+}
+
+// WithStatsAnnotations writes field statistics into the schema, for data
+// catalogs: every schema node gets "x-sample-count" (the number of values
+// observed), "x-type-counts" (their count per type) and "x-null-count" (the
+// number of times the property was null), and every property gets
+// "x-occurrence" (the share of its objects holding it, nulls included). Load
+// reads the counts back, so that required fields and format tolerance keep
+// exact counts when more samples are added to a loaded schema.
+func WithStatsAnnotations() Option {
+	return func(g *Generator) {
+		g.statsAnnotations = true
+	}
+}
+
+// WithRedaction keeps personal data and credentials out of the examples of the
+// schema (WithExamples, WithRootExamples). An example is redacted when its field
+// name hints at sensitive data ("password", "api_key", "ssn", "lastName",
+// "email", ...), when the field has the email, phone, ipv4 or ipv6 format or
+// holds JWTs, when the value itself is an email or a JWT, or when one of
+// redactors says so. Redacted values are replaced with synthetic ones where
+// possible (user@example.com, 192.0.2.1, a value matching the inferred pattern)
+// and masked otherwise. The content schemas of embedded JSON and JWT claims are
+// redacted too, and so is JSON text holding sensitive fields. RedactedPaths
+// lists the fields that were redacted.
+// Constants and defaults of redacted fields are dropped rather than replaced,
+// since a synthetic value would reject the real ones; their fields are listed
+// by RedactedPaths too.
+func WithRedaction(redactors ...Redactor) Option {
+	return func(g *Generator) {
+		g.redaction = true
+		g.redactors = append(g.redactors, redactors...)
+	}
+}
+
+// WithRootExamples emits up to n whole samples as "examples" on the root schema,
+// for documentation. They are a random sample of the distinct samples, picked
+// by hash like the values of WithExampleCount(n), with the smallest and largest
+// sample (by size of their JSON text) first.
+func WithRootExamples(n int) Option {
+	return func(g *Generator) {
+		g.rootExamples = max(n, 0)
+	}
+}
+
 // WithIndent sets the indentation string used when marshaling the schema to JSON.
 // A common value is "  " (two spaces) or "\t" (tab).
 // By default, output is compact (no indentation).
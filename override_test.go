@@ -0,0 +1,138 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestOverrideReplace(t *testing.T) {
+	generator := New(
+		WithOverride("status", &Schema{Type: "string", Enum: []any{"active", "inactive"}, Description: "Account status"}),
+		WithOverride("/user/address", &Schema{Ref: "#/definitions/address"}),
+		WithOverride("items[].sku", &Schema{Type: "string", Pattern: "^[A-Z]{2}-[0-9]{4}$"}),
+	)
+	generator.AddSample(`{"status": "active", "user": {"address": {"city": "Paris"}}, "items": [{"sku": "AB-1234", "qty": 1}]}`)
+
+	schemaJSON, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	var schema Schema
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		t.Fatalf("Failed to unmarshal schema: %v", err)
+	}
+
+	status := schema.Properties["status"]
+	if status.Description != "Account status" || len(status.Enum) != 2 || status.Const != nil {
+		t.Errorf("Expected status to be replaced by the override, got %+v", status)
+	}
+
+	address := schema.Properties["user"].Properties["address"]
+	if address.Ref != "#/definitions/address" || address.Type != nil || address.Properties != nil {
+		t.Errorf("Expected address to be a bare $ref, got %+v", address)
+	}
+
+	sku := schema.Properties["items"].Items.Properties["sku"]
+	if sku.Pattern != "^[A-Z]{2}-[0-9]{4}$" {
+		t.Errorf("Expected sku pattern, got %q", sku.Pattern)
+	}
+	// Siblings of an overridden node are still inferred, and so is required.
+	if schema.Properties["items"].Items.Properties["qty"].Type != "integer" {
+		t.Errorf("Expected qty to stay inferred")
+	}
+	if !reflect.DeepEqual(schema.Properties["items"].Items.Required, []string{"qty", "sku"}) {
+		t.Errorf("Expected qty and sku to be required, got %v", schema.Properties["items"].Items.Required)
+	}
+}
+
+func TestOverrideMerge(t *testing.T) {
+	generator := New(
+		WithMergedOverride("email", &Schema{Description: "Contact address"}),
+		WithMergedOverride("user", &Schema{
+			Required:   []string{"id"},
+			Properties: map[string]*Schema{"name": {Title: "Full name"}, "id": {Type: "integer"}},
+		}),
+		WithMergedOverride("code", &Schema{Type: "integer"}),
+	)
+	generator.AddSample(`{"email": "a@example.com", "user": {"name": "John"}, "code": "12"}`)
+	generator.AddSample(`{"email": "b@example.com", "user": {"name": "Jane"}, "code": "13"}`)
+
+	schema := generator.GetCurrentSchema()
+
+	email := schema.Properties["email"]
+	if email.Description != "Contact address" || email.Format != "email" || email.Type != "string" {
+		t.Errorf("Expected inferred email format with override description, got %+v", email)
+	}
+
+	user := schema.Properties["user"]
+	if user.Properties["name"].Title != "Full name" || user.Properties["name"].Type != "string" {
+		t.Errorf("Expected merged name property, got %+v", user.Properties["name"])
+	}
+	if user.Properties["id"] == nil || user.Properties["id"].Type != "integer" {
+		t.Errorf("Expected id property added by override, got %+v", user.Properties["id"])
+	}
+	if !reflect.DeepEqual(user.Required, []string{"id", "name"}) {
+		t.Errorf("Expected combined required [id name], got %v", user.Required)
+	}
+
+	// A different type discards the inferred string keywords.
+	code := schema.Properties["code"]
+	if code.Type != "integer" || code.Const != nil {
+		t.Errorf("Expected plain integer for code, got %+v", code)
+	}
+}
+
+func TestOverrideNotShared(t *testing.T) {
+	override := &Schema{Type: "object", Properties: map[string]*Schema{"a": {Type: "string"}}}
+	generator := New(WithOverride("**.meta", override))
+	generator.AddSample(`{"meta": {}, "nested": {"meta": {}}}`)
+
+	schema := generator.GetCurrentSchema()
+	schema.Properties["meta"].Properties["a"].Type = "integer"
+
+	if override.Properties["a"].Type != "string" {
+		t.Error("Expected the override schema to be copied, not shared")
+	}
+	if schema.Properties["nested"].Properties["meta"].Properties["a"].Type != "string" {
+		t.Error("Expected each matching node to get its own copy")
+	}
+}
+
+func TestMergedOverrideWithPredefined(t *testing.T) {
+	generator := New(
+		WithPredefined("when", DateTime),
+		WithMergedOverride("when", &Schema{Description: "Event time"}),
+	)
+	generator.AddSample(`{"when": "soon"}`)
+
+	when := generator.GetCurrentSchema().Properties["when"]
+	if when.Format != "date-time" || when.Description != "Event time" {
+		t.Errorf("Expected predefined date-time with override description, got %+v", when)
+	}
+}
+
+func TestOverrideRefLoad(t *testing.T) {
+	generator := New(WithOverride("address", &Schema{Ref: "#/definitions/Address"}))
+	generator.AddSample(`{"id": 1, "address": {"city": "Paris"}}`)
+	schemaJSON, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	// The loading generator does not know the override: the $ref is kept as loaded.
+	restored := New()
+	if err := restored.Load(schemaJSON); err != nil {
+		t.Fatalf("Failed to load schema with $ref: %v", err)
+	}
+	restored.AddSample(`{"id": 2, "address": {"city": "Lyon", "zip": "69001"}}`)
+
+	schema := restored.GetCurrentSchema()
+	address := schema.Properties["address"]
+	if address == nil || address.Ref != "#/definitions/Address" || address.Type != nil || address.Properties != nil {
+		t.Errorf("Expected address to stay a bare $ref after Load, got %+v", address)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"address", "id"}) {
+		t.Errorf("Expected address and id to be required, got %v", schema.Required)
+	}
+}
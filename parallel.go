@@ -0,0 +1,221 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelGenerator is a Generator variant for ingesting samples from many
+// goroutines at once. A plain Generator serialises every AddParsedSample call
+// behind a single mutex; ParallelGenerator instead spreads samples over several
+// independent shards, each with its own SchemaNode tree and lock, and merges the
+// shard trees when the schema is requested.
+//
+// The merged schema is identical to the one a Generator would produce from the
+// same samples, except for examples (WithExamples): the first value of each field
+// is taken from the first shard that saw it rather than from the first sample
+// overall. Sampling strategies (WithSampling) decide for the whole stream, in
+// the order samples are added; Reservoir sampling rebuilds the tree from a single
+// reservoir, so it uses a single shard.
+type ParallelGenerator struct {
+	opts       []Option
+	shards     []*Generator
+	next       atomic.Uint64 // round-robin cursor used to pick a shard
+	accepted   atomic.Int64  // samples admitted so far, for maxSamples
+	version    atomic.Uint64 // bumped after every added sample
+	maxSamples int
+
+	// sampler makes the sampling decisions of all shards, under its own lock;
+	// nil when every sample is observed or with a single shard.
+	sampler *Generator
+
+	mu            sync.Mutex // guards the merged cache below
+	merged        *Generator
+	mergedVersion uint64
+}
+
+// NewParallel creates a ParallelGenerator with the given number of shards,
+// configured with the same options as New. If workers is zero or negative,
+// runtime.GOMAXPROCS(0) shards are used.
+func NewParallel(workers int, opts ...Option) *ParallelGenerator {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	sampler := New(opts...)
+	switch sampler.sampling.kind {
+	case samplingAll:
+		sampler = nil
+	case samplingReservoir:
+		workers, sampler = 1, nil
+	}
+
+	p := &ParallelGenerator{
+		opts:    opts,
+		shards:  make([]*Generator, workers),
+		sampler: sampler,
+	}
+	for i := range p.shards {
+		p.shards[i] = New(opts...)
+	}
+
+	// The sample limit and the sampling strategy apply to the generator as a
+	// whole, not to each shard. A single shard applies them itself.
+	if workers > 1 {
+		p.maxSamples = p.shards[0].maxSamples
+		for _, shard := range p.shards {
+			shard.maxSamples = 0
+			if sampler != nil {
+				shard.sampling = SamplingStrategy{}
+			}
+		}
+	} else {
+		p.sampler = nil
+	}
+
+	return p
+}
+
+// AddSample adds a JSON sample to the generator.
+// Thread-safe: intended to be called concurrently from multiple goroutines.
+func (p *ParallelGenerator) AddSample(jsonData string) error {
+	data, err := p.shards[0].parseSample([]byte(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return p.AddParsedSample(data)
+}
+
+// AddParsedSample adds an already-parsed JSON value to the generator.
+// The sample is recorded in the first idle shard, starting from a round-robin
+// position, so concurrent callers rarely wait for each other.
+// Thread-safe: intended to be called concurrently from multiple goroutines.
+func (p *ParallelGenerator) AddParsedSample(data interface{}) error {
+	admitted, structureOnly := p.admit(data)
+	if !admitted {
+		return nil
+	}
+
+	shard := p.acquireShard()
+	if p.sampler != nil {
+		shard.observeAdmitted(data, structureOnly)
+	} else {
+		shard.observeSample(data)
+	}
+	shard.mu.Unlock()
+	p.version.Add(1)
+
+	return nil
+}
+
+// admit applies the sample limit and the sampling strategy to data, for all
+// shards at once. It reports whether data is observed and, for
+// StructureOnlyAfter, whether only its structure is recorded.
+func (p *ParallelGenerator) admit(data interface{}) (admitted, structureOnly bool) {
+	if p.sampler == nil {
+		return p.maxSamples <= 0 || p.accepted.Add(1) <= int64(p.maxSamples), false
+	}
+
+	s := p.sampler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxSamples > 0 && s.sampleCount >= s.maxSamples {
+		return false, false
+	}
+	if !s.admitSample(data) {
+		return false, false
+	}
+	structureOnly = s.structureOnlyNext()
+	s.sampleCount++
+	return true, structureOnly
+}
+
+// acquireShard locks and returns a shard, preferring one that is not in use.
+func (p *ParallelGenerator) acquireShard() *Generator {
+	start := int(p.next.Add(1) % uint64(len(p.shards)))
+	for i := range p.shards {
+		shard := p.shards[(start+i)%len(p.shards)]
+		if shard.mu.TryLock() {
+			return shard
+		}
+	}
+	// Every shard is busy: wait for the round-robin one.
+	shard := p.shards[start]
+	shard.mu.Lock()
+	return shard
+}
+
+// mergedGenerator returns a Generator holding the merge of all shards, reusing
+// the previous merge when no sample has been added since.
+// Must be called with p.mu held.
+func (p *ParallelGenerator) mergedGenerator() *Generator {
+	version := p.version.Load()
+	if p.merged != nil && p.mergedVersion == version {
+		return p.merged
+	}
+
+	merged := New(p.opts...)
+	for _, shard := range p.shards {
+		shard.mu.Lock()
+		shard.syncTree()
+		merged.rootNode.merge(shard.rootNode)
+		merged.sampleCount += shard.sampleCount
+		shard.mu.Unlock()
+	}
+
+	p.merged = merged
+	p.mergedVersion = version
+	return merged
+}
+
+// Generate merges the shards and generates a JSON schema from all samples.
+// Thread-safe: can be called concurrently with AddSample/AddParsedSample.
+func (p *ParallelGenerator) Generate() (string, error) {
+	var buf bytes.Buffer
+	if err := p.GenerateTo(&buf); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+// GenerateTo merges the shards and writes the JSON schema directly to w.
+// Thread-safe: can be called concurrently with AddSample/AddParsedSample.
+func (p *ParallelGenerator) GenerateTo(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.mergedGenerator().GenerateTo(w)
+}
+
+// Stats merges the shards and returns statistics about every field.
+// Thread-safe: can be called concurrently with AddSample/AddParsedSample.
+func (p *ParallelGenerator) Stats() *Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.mergedGenerator().Stats()
+}
+
+// RedactedPaths merges the shards and returns the paths redacted in the current
+// schema (WithRedaction).
+// Thread-safe: can be called concurrently with AddSample/AddParsedSample.
+func (p *ParallelGenerator) RedactedPaths() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.mergedGenerator().RedactedPaths()
+}
+
+// GetCurrentSchema merges the shards and returns the current schema as a Schema object.
+// Thread-safe: can be called concurrently with AddSample/AddParsedSample.
+func (p *ParallelGenerator) GetCurrentSchema() *Schema {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.mergedGenerator().GetCurrentSchema()
+}
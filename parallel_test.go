@@ -0,0 +1,221 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// parallelTestSamples returns a varied set of samples covering optional fields,
+// union types, formats, const values, nulls, arrays of objects and nesting.
+func parallelTestSamples(n int) []string {
+	samples := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		sample := fmt.Sprintf(`{"id": %d, "kind": "event", "email": "user%d@example.com", "tags": ["a", "b"]`, i, i)
+		if i%3 == 0 {
+			sample += fmt.Sprintf(`, "score": %d.5`, i)
+		}
+		if i%5 == 0 {
+			sample += `, "note": null`
+		} else {
+			sample += fmt.Sprintf(`, "note": "n%d"`, i)
+		}
+		if i%7 == 0 {
+			sample += `, "value": "text"`
+		} else {
+			sample += `, "value": 42`
+		}
+		if i == n/2 {
+			sample += `, "email2": "not-an-email"`
+		} else {
+			sample += `, "email2": "x@example.org"`
+		}
+		sample += fmt.Sprintf(`, "items": [{"sku": "S%d", "qty": %d}, {"sku": "T"}]`, i, i%4)
+		sample += `, "meta": {"created_at": "2023-01-15T10:30:00Z", "source": {"host": "10.0.0.1"}}}`
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+func TestParallelMatchesSequential(t *testing.T) {
+	samples := parallelTestSamples(500)
+
+	sequential := New()
+	for _, sample := range samples {
+		if err := sequential.AddSample(sample); err != nil {
+			t.Fatalf("Failed to add sample: %v", err)
+		}
+	}
+	want, err := sequential.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	for _, workers := range []int{1, 4, 32} {
+		parallel := NewParallel(workers)
+		var wg sync.WaitGroup
+		for g := 0; g < 32; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := g; i < len(samples); i += 32 {
+					if err := parallel.AddSample(samples[i]); err != nil {
+						t.Errorf("Failed to add sample: %v", err)
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		got, err := parallel.Generate()
+		if err != nil {
+			t.Fatalf("Failed to generate schema: %v", err)
+		}
+		if got != want {
+			t.Errorf("workers=%d: parallel schema differs from sequential\ngot:  %s\nwant: %s", workers, got, want)
+		}
+	}
+}
+
+func TestParallelConcurrentReadWrite(t *testing.T) {
+	parallel := NewParallel(4)
+	samples := parallelTestSamples(200)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < len(samples); i += 8 {
+				parallel.AddSample(samples[i])
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				parallel.GetCurrentSchema()
+				parallel.Generate()
+			}
+		}()
+	}
+	wg.Wait()
+
+	schema := parallel.GetCurrentSchema()
+	if schema.Properties["id"] == nil {
+		t.Fatal("Expected 'id' property to be defined")
+	}
+	if schema.Properties["meta"].Properties["source"].Properties["host"].Format != "ipv4" {
+		t.Errorf("Expected nested host format ipv4, got %v", schema.Properties["meta"].Properties["source"].Properties["host"].Format)
+	}
+}
+
+func TestParallelMaxSamples(t *testing.T) {
+	parallel := NewParallel(4, WithMaxSamples(10))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				parallel.AddSample(fmt.Sprintf(`{"g%d": %d}`, g, i))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	merged := parallel.mergedGenerator()
+	if merged.sampleCount != 10 {
+		t.Errorf("Expected 10 samples across all shards, got %d", merged.sampleCount)
+	}
+}
+
+func TestParallelOptions(t *testing.T) {
+	parallel := NewParallel(2,
+		WithPredefined("created_at", DateTime),
+		WithSchemaVersion(Draft06),
+	)
+	parallel.AddSample(`{"created_at": "yesterday", "n": 1}`)
+	parallel.AddSample(`{"created_at": "today", "n": 2}`)
+
+	schemaJSON, err := parallel.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	var schema Schema
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		t.Fatalf("Failed to unmarshal schema: %v", err)
+	}
+	if schema.Schema != string(Draft06) {
+		t.Errorf("Expected Draft06, got %s", schema.Schema)
+	}
+	if schema.Properties["created_at"].Format != "date-time" {
+		t.Errorf("Expected predefined date-time format, got %v", schema.Properties["created_at"].Format)
+	}
+}
+
+func TestParallelNoSamples(t *testing.T) {
+	parallel := NewParallel(0)
+	if _, err := parallel.Generate(); err == nil {
+		t.Error("Expected error when generating without samples")
+	}
+}
+
+func BenchmarkGeneratorAddParsedSampleParallel(b *testing.B) {
+	benchmarkAddParsedSample(b, New())
+}
+
+func BenchmarkParallelGeneratorAddParsedSample(b *testing.B) {
+	benchmarkAddParsedSample(b, NewParallel(0))
+}
+
+// benchmarkAddParsedSample measures ingestion throughput from GOMAXPROCS goroutines.
+func benchmarkAddParsedSample(b *testing.B, g interface{ AddParsedSample(interface{}) error }) {
+	samples := parallelTestSamples(64)
+	parsed := make([]interface{}, len(samples))
+	for i, sample := range samples {
+		if err := json.Unmarshal([]byte(sample), &parsed[i]); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			g.AddParsedSample(parsed[i%len(parsed)])
+			i++
+		}
+	})
+}
+
+func TestParallelSamplingMatchesSequential(t *testing.T) {
+	timestamp := func(sample interface{}) (time.Time, bool) {
+		hour := sample.(map[string]interface{})["hour"].(float64)
+		return time.Unix(int64(hour)*3600, 0), true
+	}
+	strategies := map[string]SamplingStrategy{
+		"every-kth":      EveryKth(2),
+		"time-bucketed":  TimeBucketed(time.Hour, 1, timestamp),
+		"structure-only": StructureOnlyAfter(3),
+		"reservoir":      Reservoir(3),
+	}
+	for name, strategy := range strategies {
+		sequential := New(WithSampling(strategy), WithMaxSamples(6))
+		parallel := NewParallel(4, WithSampling(strategy), WithMaxSamples(6))
+		for i := 0; i < 16; i++ {
+			sample := fmt.Sprintf(`{"f%d": true, "hour": %d, "k": "v%d"}`, i, i/2, i)
+			sequential.AddSample(sample)
+			parallel.AddSample(sample)
+		}
+
+		want, _ := sequential.Generate()
+		if got, _ := parallel.Generate(); got != want {
+			t.Errorf("%s: parallel schema differs from sequential\ngot:  %s\nwant: %s", name, got, want)
+		}
+	}
+}
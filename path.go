@@ -0,0 +1,139 @@
+package jsonschema
+
+import "strings"
+
+// Special path segments. A node path is the list of property names leading to a
+// node, with itemsSegment standing for "the items of this array".
+const (
+	itemsSegment = "[]" // array items
+	anySegment   = "*"  // any single property or array items
+	deepSegment  = "**" // any number of segments, including none
+)
+
+// pathPattern is a parsed path expression addressing nodes of the schema tree,
+// e.g. ["user", "address", "zip"] or ["items", "[]", "sku"].
+type pathPattern []string
+
+// parsePath parses a path expression in one of two notations:
+//
+//   - JSON Pointer (RFC 6901), recognised by its leading '/':
+//     "/user/address/zip", "/items/*/sku", "/items/0/sku". "~0" and "~1" are
+//     unescaped; array indexes and "[]" address array items.
+//   - dotted paths: "user.address.zip", "items[].sku", "items[*].sku".
+//
+// In both notations "*" matches any single property or array items and "**"
+// matches any number of levels. The empty string addresses the root.
+// Parsing is lenient: anything that is not special is taken literally.
+func parsePath(path string) pathPattern {
+	if path == "" {
+		return pathPattern{}
+	}
+
+	if strings.HasPrefix(path, "/") {
+		parts := strings.Split(path[1:], "/")
+		pattern := make(pathPattern, len(parts))
+		for i, part := range parts {
+			part = strings.ReplaceAll(part, "~1", "/")
+			pattern[i] = strings.ReplaceAll(part, "~0", "~")
+		}
+		return pattern
+	}
+
+	var pattern pathPattern
+	for _, part := range strings.Split(path, ".") {
+		// Peel trailing "[]", "[*]" or "[N]" suffixes off each dotted segment.
+		var suffixes int
+		for strings.HasSuffix(part, "]") {
+			open := strings.LastIndexByte(part, '[')
+			if open < 0 || !isIndexOrWildcard(part[open+1:len(part)-1]) {
+				break
+			}
+			part = part[:open]
+			suffixes++
+		}
+		if part != "" {
+			pattern = append(pattern, part)
+		}
+		for ; suffixes > 0; suffixes-- {
+			pattern = append(pattern, itemsSegment)
+		}
+	}
+	return pattern
+}
+
+// isIndexOrWildcard reports whether s is a valid content for a dotted-path
+// bracket suffix: empty, "*", or a decimal array index.
+func isIndexOrWildcard(s string) bool {
+	return s == "" || s == anySegment || isArrayIndex(s)
+}
+
+// isArrayIndex reports whether s is a non-negative decimal integer.
+func isArrayIndex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether the node path matches the pattern.
+func (p pathPattern) matches(path []string) bool {
+	if len(p) == 0 {
+		return len(path) == 0
+	}
+
+	if p[0] == deepSegment {
+		// "**" absorbs zero or more segments.
+		for i := 0; i <= len(path); i++ {
+			if p[1:].matches(path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 || !segmentMatches(p[0], path[0]) {
+		return false
+	}
+	return p[1:].matches(path[1:])
+}
+
+// segmentMatches reports whether a single pattern segment matches a node path segment.
+func segmentMatches(pattern, segment string) bool {
+	switch {
+	case pattern == anySegment:
+		return true
+	case segment == itemsSegment:
+		// Array items are addressed by "[]" or by any index, since all items
+		// of an array share a single node.
+		return pattern == itemsSegment || isArrayIndex(pattern)
+	default:
+		return pattern == segment
+	}
+}
+
+// walk calls fn for n and every descendant of n, with the path of each node.
+// The path slice is reused between calls and must not be retained by fn.
+func (n *SchemaNode) walk(path []string, fn func(path []string, node *SchemaNode)) {
+	fn(path, n)
+	n.walkChildren(path, fn)
+}
+
+// walkChildren calls walk for every child of n. The content of JSON embedded
+// in a string shares the path of the string, so its children are visited as
+// children of n.
+func (n *SchemaNode) walkChildren(path []string, fn func(path []string, node *SchemaNode)) {
+	if n.embedded != nil {
+		n.embedded.walkChildren(path, fn)
+	}
+	if n.arrayItemNode != nil {
+		n.arrayItemNode.walk(append(path, itemsSegment), fn)
+	}
+	for key, child := range n.objectProperties {
+		child.walk(append(path, key), fn)
+	}
+}
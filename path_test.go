@@ -0,0 +1,100 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want pathPattern
+	}{
+		{"", pathPattern{}},
+		{"/user/address/zip", pathPattern{"user", "address", "zip"}},
+		{"/items/*/sku", pathPattern{"items", "*", "sku"}},
+		{"/items/[]/sku", pathPattern{"items", "[]", "sku"}},
+		{"/a~1b/c~0d", pathPattern{"a/b", "c~d"}},
+		{"user.address.zip", pathPattern{"user", "address", "zip"}},
+		{"items[].sku", pathPattern{"items", "[]", "sku"}},
+		{"items[*].sku", pathPattern{"items", "[]", "sku"}},
+		{"matrix[][]", pathPattern{"matrix", "[]", "[]"}},
+		{"**.secret", pathPattern{"**", "secret"}},
+		{"created_at", pathPattern{"created_at"}},
+	}
+
+	for _, tt := range tests {
+		if got := parsePath(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPathPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    []string
+		want    bool
+	}{
+		{"user.address.zip", []string{"user", "address", "zip"}, true},
+		{"user.address.zip", []string{"user", "address"}, false},
+		{"/items/0/sku", []string{"items", "[]", "sku"}, true},
+		{"/items/*/sku", []string{"items", "[]", "sku"}, true},
+		{"user.*", []string{"user", "name"}, true},
+		{"user.*", []string{"user", "name", "first"}, false},
+		{"**.secret", []string{"secret"}, true},
+		{"**.secret", []string{"a", "[]", "b", "secret"}, true},
+		{"**.secret", []string{"secret", "x"}, false},
+		{"", []string{}, true},
+		{"items[].sku", []string{"items", "sku"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := parsePath(tt.pattern).matches(tt.path); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPredefinedPath(t *testing.T) {
+	generator := New(
+		WithPredefinedPath("user.address.zip", String),
+		WithPredefinedPath("/items/*/sku", String),
+		WithPredefinedPath("**.seen_at", DateTime),
+	)
+
+	generator.AddSample(`{"user": {"address": {"zip": 75001}}, "items": [{"sku": 123}]}`)
+	schema := generator.GetCurrentSchema()
+
+	if zip := schema.Properties["user"].Properties["address"].Properties["zip"]; zip.Type != "string" {
+		t.Errorf("Expected zip to be string, got %v", zip.Type)
+	}
+	if sku := schema.Properties["items"].Items.Properties["sku"]; sku.Type != "string" {
+		t.Errorf("Expected sku to be string, got %v", sku.Type)
+	}
+
+	// Nodes created by later samples must pick up the overrides too.
+	generator.AddSample(`{"user": {"seen_at": 1700000000}, "items": [{"seen_at": "yesterday"}]}`)
+	schema = generator.GetCurrentSchema()
+
+	if seen := schema.Properties["user"].Properties["seen_at"]; seen.Type != "string" || seen.Format != "date-time" {
+		t.Errorf("Expected user.seen_at to be date-time, got %v %q", seen.Type, seen.Format)
+	}
+	if seen := schema.Properties["items"].Items.Properties["seen_at"]; seen.Format != "date-time" {
+		t.Errorf("Expected items[].seen_at to be date-time, got %q", seen.Format)
+	}
+}
+
+func TestPredefinedFieldNameIsLiteral(t *testing.T) {
+	// WithPredefined keeps treating its argument as a top-level field name.
+	generator := New(WithPredefined("a.b", String))
+	generator.AddSample(`{"a.b": 1, "a": {"b": 2}}`)
+	schema := generator.GetCurrentSchema()
+
+	if schema.Properties["a.b"].Type != "string" {
+		t.Errorf("Expected literal field a.b to be string, got %v", schema.Properties["a.b"].Type)
+	}
+	if schema.Properties["a"].Properties["b"].Type != "integer" {
+		t.Errorf("Expected nested a.b to stay integer, got %v", schema.Properties["a"].Properties["b"].Type)
+	}
+}
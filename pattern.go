@@ -0,0 +1,238 @@
+package jsonschema
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxPatternTokens bounds the size of an inferred pattern template; values
+// with more runs and separators are treated as free text.
+const maxPatternTokens = 16
+
+// Character classes of the runs of a pattern template.
+const (
+	classUpper = 1 << iota // A-Z
+	classLower             // a-z
+	classDigit             // 0-9
+)
+
+// patternToken is one element of a pattern template: either a run of
+// alphanumeric characters from classes, between min and max characters long,
+// or a literal separator character.
+type patternToken struct {
+	classes  int
+	min, max int
+	literal  byte
+}
+
+// tokenizePattern splits str into alphanumeric runs and separators. It reports
+// false for values that cannot be templated: non-ASCII or control characters,
+// or too many tokens.
+func tokenizePattern(str string) ([]patternToken, bool) {
+	var tokens []patternToken
+	for i := 0; i < len(str); {
+		c := str[i]
+		if class := charClass(c); class != 0 {
+			run := patternToken{}
+			j := i
+			for ; j < len(str) && charClass(str[j]) != 0; j++ {
+				run.classes |= charClass(str[j])
+			}
+			run.min, run.max = j-i, j-i
+			tokens = append(tokens, run)
+			i = j
+		} else {
+			if c < ' ' || c > '~' {
+				return nil, false
+			}
+			tokens = append(tokens, patternToken{literal: c})
+			i++
+		}
+		if len(tokens) > maxPatternTokens {
+			return nil, false
+		}
+	}
+	return tokens, len(tokens) > 0
+}
+
+// charClass returns the class of an alphanumeric ASCII character, or 0.
+func charClass(c byte) int {
+	switch {
+	case c >= 'A' && c <= 'Z':
+		return classUpper
+	case c >= 'a' && c <= 'z':
+		return classLower
+	case c >= '0' && c <= '9':
+		return classDigit
+	}
+	return 0
+}
+
+// mergePatternTemplates widens template to also cover tokens. It reports false
+// when the two do not share the same structure of runs and separators.
+func mergePatternTemplates(template, tokens []patternToken) bool {
+	if len(template) != len(tokens) {
+		return false
+	}
+	for i, tok := range tokens {
+		t := &template[i]
+		if (t.literal == 0) != (tok.literal == 0) || t.literal != tok.literal {
+			return false
+		}
+		t.classes |= tok.classes
+		t.min = min(t.min, tok.min)
+		t.max = max(t.max, tok.max)
+	}
+	return true
+}
+
+// observePattern folds str into the pattern template of the node. The first
+// value that does not fit the template turns inference off for the node.
+func (n *SchemaNode) observePattern(str string) {
+	if n.patternOff {
+		return
+	}
+	tokens, ok := tokenizePattern(str)
+	switch {
+	case !ok:
+	case n.patternTemplate == nil:
+		n.patternTemplate = tokens
+		return
+	case mergePatternTemplates(n.patternTemplate, tokens):
+		return
+	}
+	n.patternOff = true
+	n.patternTemplate = nil
+}
+
+// mergePattern folds the pattern template of other into n.
+func (n *SchemaNode) mergePattern(other *SchemaNode) {
+	switch {
+	case n.patternOff || other.patternTemplate == nil && !other.patternOff:
+	case other.patternOff:
+		n.patternOff, n.patternTemplate = true, nil
+	case n.patternTemplate == nil:
+		n.patternTemplate = append([]patternToken{}, other.patternTemplate...)
+	case !mergePatternTemplates(n.patternTemplate, other.patternTemplate):
+		n.patternOff, n.patternTemplate = true, nil
+	}
+}
+
+// applyPattern emits the inferred pattern once enough strings were observed,
+// unless the schema already says more: a format, a pattern or a const.
+// Templates that only describe free-form words ("Alice", "Bob") are not emitted.
+func (n *SchemaNode) applyPattern(schema *Schema, opts *renderOptions) {
+	if opts.patternMinSamples <= 0 || n.patternTemplate == nil || n.stringCount < opts.patternMinSamples ||
+		schema.Format != "" || schema.Pattern != "" || schema.Const != nil || !isStructured(n.patternTemplate) {
+		return
+	}
+	schema.Pattern = renderPattern(n.patternTemplate)
+}
+
+// isStructured reports whether a template describes an identifier rather than
+// a word: it has a separator, mixes digits with letters, or only has runs of
+// fixed length.
+func isStructured(template []patternToken) bool {
+	fixed := true
+	for _, t := range template {
+		if t.literal != 0 {
+			return true
+		}
+		if t.classes&classDigit != 0 && t.classes != classDigit {
+			return true
+		}
+		fixed = fixed && t.min == t.max
+	}
+	return fixed
+}
+
+// renderPattern renders a template as an anchored regular expression such as
+// ^[A-Z]{2}-[0-9]{4}-[A-Z]$. A run of exactly one character has no quantifier.
+func renderPattern(template []patternToken) string {
+	var b strings.Builder
+	b.WriteByte('^')
+	for _, t := range template {
+		if t.literal != 0 {
+			b.WriteString(regexp.QuoteMeta(string(t.literal)))
+			continue
+		}
+		b.WriteByte('[')
+		if t.classes&classDigit != 0 {
+			b.WriteString("0-9")
+		}
+		if t.classes&classUpper != 0 {
+			b.WriteString("A-Z")
+		}
+		if t.classes&classLower != 0 {
+			b.WriteString("a-z")
+		}
+		b.WriteByte(']')
+		if t.min == 1 && t.max == 1 {
+			continue
+		}
+		b.WriteByte('{')
+		b.WriteString(strconv.Itoa(t.min))
+		if t.max != t.min {
+			b.WriteByte(',')
+			b.WriteString(strconv.Itoa(t.max))
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte('$')
+	return b.String()
+}
+
+// inferredPatternSyntax matches the patterns written by renderPattern.
+var inferredPatternSyntax = regexp.MustCompile(`^\^(?:\[(?:0-9)?(?:A-Z)?(?:a-z)?\](?:\{[0-9]+(?:,[0-9]+)?\})?|\\.|[^\\\[\]{}^$])*\$$`)
+
+// parsePatternTemplate parses a pattern written by renderPattern back into a
+// template, so that Load can resume inference. It reports false for any other
+// pattern.
+func parsePatternTemplate(pattern string) ([]patternToken, bool) {
+	if !inferredPatternSyntax.MatchString(pattern) {
+		return nil, false
+	}
+	var template []patternToken
+	body := pattern[1 : len(pattern)-1]
+	for i := 0; i < len(body); {
+		switch body[i] {
+		case '[':
+			end := strings.IndexByte(body[i:], ']') + i
+			classes, counts := body[i+1:end], "1"
+			if end+1 < len(body) && body[end+1] == '{' {
+				i = end + 1
+				end = strings.IndexByte(body[i:], '}') + i
+				counts = body[i+1 : end]
+			}
+			t := patternToken{}
+			if strings.Contains(classes, "0-9") {
+				t.classes |= classDigit
+			}
+			if strings.Contains(classes, "A-Z") {
+				t.classes |= classUpper
+			}
+			if strings.Contains(classes, "a-z") {
+				t.classes |= classLower
+			}
+			lo, hi, found := strings.Cut(counts, ",")
+			t.min, _ = strconv.Atoi(lo)
+			t.max = t.min
+			if found {
+				t.max, _ = strconv.Atoi(hi)
+			}
+			if t.classes == 0 {
+				return nil, false
+			}
+			template = append(template, t)
+			i = end + 1
+		case '\\':
+			template = append(template, patternToken{literal: body[i+1]})
+			i += 2
+		default:
+			template = append(template, patternToken{literal: body[i]})
+			i++
+		}
+	}
+	return template, len(template) > 0
+}
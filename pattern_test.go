@@ -0,0 +1,118 @@
+package jsonschema
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestPatternInference(t *testing.T) {
+	generator := New(WithPatternInference(3))
+	generator.AddSample(`{"sku": "AB-1234-X", "code": "a1b2", "name": "Alice", "zip": "75001", "note": "hello world"}`)
+	generator.AddSample(`{"sku": "CD-5678-Y", "code": "c3", "name": "Bob", "zip": "10115", "note": "bye"}`)
+	generator.AddSample(`{"sku": "EF-9012-Z", "code": "x9y8z7", "name": "Carol", "zip": "20095", "note": "two words here"}`)
+
+	schema := generator.GetCurrentSchema()
+	tests := map[string]string{
+		"sku":  `^[A-Z]{2}-[0-9]{4}-[A-Z]$`,
+		"code": `^[0-9a-z]{2,6}$`,
+		"zip":  `^[0-9]{5}$`,
+		"name": "",
+		"note": "",
+	}
+	for field, want := range tests {
+		if got := schema.Properties[field].Pattern; got != want {
+			t.Errorf("%s: expected pattern %q, got %q", field, want, got)
+		}
+	}
+	re := regexp.MustCompile(schema.Properties["sku"].Pattern)
+	for _, value := range []string{"AB-1234-X", "EF-9012-Z"} {
+		if !re.MatchString(value) {
+			t.Errorf("Expected %q to match %s", value, re)
+		}
+	}
+}
+
+func TestPatternInferenceMinSamples(t *testing.T) {
+	generator := New(WithPatternInference(3))
+	generator.AddSample(`{"sku": "AB-1234-X"}`)
+	generator.AddSample(`{"sku": "CD-5678-Y"}`)
+
+	if pattern := generator.GetCurrentSchema().Properties["sku"].Pattern; pattern != "" {
+		t.Errorf("Expected no pattern before 3 strings, got %q", pattern)
+	}
+}
+
+func TestPatternInferenceDiverges(t *testing.T) {
+	generator := New(WithPatternInference(1))
+	generator.AddSample(`{"id": "AB-1234", "long": "a-b-c-d-e-f-g-h-i", "accent": "é-1"}`)
+	generator.AddSample(`{"id": "AB1234", "long": "a-b-c-d-e-f-g-h-i", "accent": "é-2"}`)
+
+	schema := generator.GetCurrentSchema()
+	for _, field := range []string{"id", "long", "accent"} {
+		if pattern := schema.Properties[field].Pattern; pattern != "" {
+			t.Errorf("%s: expected no pattern, got %q", field, pattern)
+		}
+	}
+}
+
+func TestPatternInferenceDefersToFormats(t *testing.T) {
+	generator := New(WithPatternInference(1))
+	generator.AddSample(`{"id": "550e8400-e29b-41d4-a716-446655440000", "fixed": "AB-1"}`)
+	generator.AddSample(`{"id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "fixed": "AB-1"}`)
+
+	schema := generator.GetCurrentSchema()
+	if id := schema.Properties["id"]; id.Format != "uuid" || id.Pattern != "" {
+		t.Errorf("Expected uuid format without inferred pattern, got %+v", id)
+	}
+	if fixed := schema.Properties["fixed"]; fixed.Pattern != "" {
+		t.Errorf("Expected no pattern on a const field, got %q", fixed.Pattern)
+	}
+}
+
+func TestPatternInferenceLoad(t *testing.T) {
+	generator := New(WithPatternInference(1))
+	generator.AddSample(`{"sku": "AB_1234.x"}`)
+	generator.AddSample(`{"sku": "CD_5678.y"}`)
+	saved, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if pattern := generator.GetCurrentSchema().Properties["sku"].Pattern; pattern != `^[A-Z]{2}_[0-9]{4}\.[a-z]$` {
+		t.Errorf("Expected single-character run without quantifier, got %q", pattern)
+	}
+
+	restored := New(WithPatternInference(1))
+	if err := restored.Load(saved); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	restored.AddSample(`{"sku": "EFG_12.yz"}`)
+	restored.AddSample(`{"sku": "HIJ_3456.z"}`)
+	if got, want := restored.GetCurrentSchema().Properties["sku"].Pattern, `^[A-Z]{2,3}_[0-9]{2,4}\.[a-z]{1,2}$`; got != want {
+		t.Errorf("Expected widened pattern %q, got %q", want, got)
+	}
+
+	restored.AddSample(`{"sku": "free text"}`)
+	if got := restored.GetCurrentSchema().Properties["sku"].Pattern; got != "" {
+		t.Errorf("Expected loaded pattern to be dropped after divergence, got %q", got)
+	}
+}
+
+func TestPatternInferenceParallel(t *testing.T) {
+	sequential := New(WithPatternInference(1))
+	parallel := NewParallel(3, WithPatternInference(1))
+	for i := 0; i < 30; i++ {
+		sample := fmt.Sprintf(`{"sku": "SKU-%d", "ref": "R%d-%c"}`, i*37, i, 'a'+i%26)
+		sequential.AddSample(sample)
+		parallel.AddSample(sample)
+	}
+
+	want, _ := sequential.Generate()
+	got, err := parallel.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if got != want {
+		t.Errorf("Parallel schema differs from sequential\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func isCorpEmail(s string) bool {
+	return strings.HasSuffix(s, "@corp.example.com")
+}
+
+func TestFormatPriority(t *testing.T) {
+	sample := `{"email": "alice@corp.example.com"}`
+
+	// Same priority as the built-ins: registration order keeps email first.
+	generator := New(WithCustomFormat("corp-email", isCorpEmail))
+	generator.AddSample(sample)
+	if format := generator.GetCurrentSchema().Properties["email"].Format; format != "email" {
+		t.Errorf("Expected email format, got %q", format)
+	}
+
+	generator = New(WithFormat(CustomFormat{Name: "corp-email", Detector: isCorpEmail, Priority: 10}))
+	generator.AddSample(sample)
+	if format := generator.GetCurrentSchema().Properties["email"].Format; format != "corp-email" {
+		t.Errorf("Expected the higher priority corp-email format, got %q", format)
+	}
+
+	// Once a value rules the specific format out, the general one remains.
+	generator.AddSample(`{"email": "bob@example.org"}`)
+	if format := generator.GetCurrentSchema().Properties["email"].Format; format != "email" {
+		t.Errorf("Expected email format after elimination, got %q", format)
+	}
+}
+
+func TestFormatPriorityTies(t *testing.T) {
+	generator := New(
+		WithoutBuiltInFormats(),
+		WithFormat(CustomFormat{Name: "first", Pattern: `^a`, Priority: 1}),
+		WithFormat(CustomFormat{Name: "second", Pattern: `^a`, Priority: 1}),
+		WithFormat(CustomFormat{Name: "low", Pattern: `^a`}),
+	)
+	generator.AddSample(`{"v": "abc"}`)
+
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["v"].Format != "first" {
+		t.Errorf("Expected first registered format to win the tie, got %q", schema.Properties["v"].Format)
+	}
+	if schema.Properties["v"].Pattern != "^a" {
+		t.Errorf("Expected pattern of the format, got %q", schema.Properties["v"].Pattern)
+	}
+}
+
+func TestFormatCandidates(t *testing.T) {
+	generator := New(
+		WithFormat(CustomFormat{Name: "corp-email", Detector: isCorpEmail, Priority: 10}),
+		WithFormatCandidates(),
+	)
+	generator.AddSample(`{"email": "alice@corp.example.com", "id": "550e8400-e29b-41d4-a716-446655440000"}`)
+
+	schema := generator.GetCurrentSchema()
+	want := []string{"corp-email", "email"}
+	if got := schema.Properties["email"].Extensions["x-format-candidates"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected candidates %v, got %v", want, got)
+	}
+	if schema.Properties["id"].Extensions != nil {
+		t.Errorf("Expected no candidates for a single match, got %v", schema.Properties["id"].Extensions)
+	}
+}
+
+func TestFormatPriorityWithTolerance(t *testing.T) {
+	generator := New(
+		WithFormatTolerance(0.6),
+		WithFormat(CustomFormat{Name: "corp-email", Detector: isCorpEmail, Priority: 10}),
+	)
+	generator.AddSample(`{"email": "a@corp.example.com"}`)
+	generator.AddSample(`{"email": "b@corp.example.com"}`)
+	generator.AddSample(`{"email": "c@example.org"}`)
+
+	// corp-email matches fewer values than email but passes the threshold.
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["email"].Format != "corp-email" {
+		t.Errorf("Expected corp-email format, got %q", schema.Properties["email"].Format)
+	}
+	if mismatches := schema.Properties["email"].Extensions["x-format-mismatches"]; mismatches != 1 {
+		t.Errorf("Expected one mismatch, got %v", mismatches)
+	}
+}
+
+func TestWithFormatInvalidPattern(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for an invalid pattern")
+		}
+	}()
+	WithFormat(CustomFormat{Name: "broken", Pattern: `(`})
+}
+
+func TestWithFormatPack(t *testing.T) {
+	pack := []CustomFormat{
+		{Name: "sku", Pattern: `^SKU-[0-9]+$`},
+		{Name: "corp-email", Detector: isCorpEmail, Priority: 10},
+	}
+	generator := New(WithFormatPack(pack))
+	generator.AddSample(`{"sku": "SKU-1", "email": "alice@corp.example.com"}`)
+
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["sku"].Format != "sku" || schema.Properties["sku"].Pattern != `^SKU-[0-9]+$` {
+		t.Errorf("Expected sku pattern format, got %+v", schema.Properties["sku"])
+	}
+	if schema.Properties["email"].Format != "corp-email" {
+		t.Errorf("Expected corp-email format, got %q", schema.Properties["email"].Format)
+	}
+}
@@ -0,0 +1,279 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Redactor reports whether an example value must be redacted. path is the
+// JSON Pointer of the field, with "[]" standing for array items, such as
+// "/users/[]/email"; schema is the schema inferred for the field.
+type Redactor func(path string, schema *Schema, value any) bool
+
+// redactedText replaces sensitive strings that have no synthetic substitute.
+const redactedText = "<redacted>"
+
+// sensitiveFieldParts are the field name fragments that mark a field as
+// sensitive. Names are compared in lower case without '_', '-' and '.'.
+var sensitiveFieldParts = []string{
+	"password", "passwd", "secret", "token", "apikey", "credential",
+	"ssn", "socialsecurity", "creditcard", "cardnumber", "cvv", "iban",
+	"firstname", "lastname", "fullname", "surname", "username",
+	"email", "phone", "birth",
+}
+
+// sensitiveFieldNames are the field names that mark a field as sensitive only
+// when they are the whole name.
+var sensitiveFieldNames = []string{"name", "pin", "dob"}
+
+// syntheticValues are the documentation-safe substitutes for values of a
+// format: reserved domains and addresses (RFC 2606, 5737, 3849) and a fictional
+// phone number.
+var syntheticValues = map[string]string{
+	"email":     "user@example.com",
+	"idn-email": "user@example.com",
+	"phone":     "+15555550100",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+}
+
+// isSensitiveField reports whether a field name hints at personal data or
+// credentials, e.g. "password", "api_key" or "lastName".
+func isSensitiveField(name string) bool {
+	name = strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(name))
+	if slices.Contains(sensitiveFieldNames, name) {
+		return true
+	}
+	for _, part := range sensitiveFieldParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redaction redacts the example values of a schema and records their paths.
+type redaction struct {
+	redactors []Redactor
+	paths     map[string]bool
+}
+
+// isSensitive reports whether value, an example of the field at path with the
+// given schema, must be redacted: by the name of the field, by its format or
+// media type, by the value itself, or by a user Redactor.
+func (r *redaction) isSensitive(path []string, schema *Schema, value any) bool {
+	// Array items are named after their array: "tokens": ["..."]
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] != itemsSegment {
+			if isSensitiveField(path[i]) {
+				return true
+			}
+			break
+		}
+	}
+	if schema != nil {
+		if _, ok := syntheticValues[schema.Format]; ok {
+			return true
+		}
+		if schema.ContentMediaType == "application/jwt" {
+			return true
+		}
+	}
+	if s, ok := value.(string); ok && (isEmail(s) || isJWT(s)) {
+		return true
+	}
+	if len(r.redactors) > 0 {
+		pointer := pointerOf(path)
+		for _, redactor := range r.redactors {
+			if redactor(pointer, schema, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactSchema redacts the examples of schema and of every schema below it,
+// including the content schemas of embedded JSON and JWT claims, and drops
+// their sensitive constants and defaults.
+func (r *redaction) redactSchema(path []string, schema *Schema) {
+	if schema == nil {
+		return
+	}
+	if schema.Example != nil {
+		schema.Example = r.redactValue(path, schema, schema.Example)
+	}
+	for i, example := range schema.Examples {
+		schema.Examples[i] = r.redactValue(path, schema, example)
+	}
+	if schema.Examples != nil {
+		schema.Examples = dedupExamples(schema.Examples)
+	}
+	// A synthetic const or default would reject or misstate real values, so
+	// sensitive ones are dropped
+	if schema.Const != nil && !equalJSON(r.redactValue(path, schema, schema.Const), schema.Const) {
+		schema.Const = nil
+	}
+	if schema.Default != nil && !equalJSON(r.redactValue(path, schema, schema.Default), schema.Default) {
+		schema.Default = nil
+	}
+
+	r.redactSchema(append(path, itemsSegment), schema.Items)
+	// Content is inferred at the path of its string, see observeEmbedded
+	r.redactSchema(path, embeddedContent(schema))
+	if claims, ok := schema.Extensions["x-jwt-claims"].(*Schema); ok {
+		r.redactSchema(path, claims)
+	}
+	for key, prop := range schema.Properties {
+		r.redactSchema(append(path, key), prop)
+	}
+}
+
+// embeddedContent returns the schema inferred for the JSON held by a string
+// schema (WithEmbeddedJSON), whichever keyword carries it, or nil.
+func embeddedContent(schema *Schema) *Schema {
+	if schema.ContentSchema != nil {
+		return schema.ContentSchema
+	}
+	content, _ := schema.Extensions["x-contentSchema"].(*Schema)
+	return content
+}
+
+// redactEmbedded redacts the sensitive parts of s, a string holding JSON
+// described by content, and encodes the JSON text again. s is returned as is
+// when nothing in it is sensitive.
+func (r *redaction) redactEmbedded(path []string, content *Schema, s string) any {
+	var decoded interface{}
+	if json.Unmarshal([]byte(s), &decoded) != nil {
+		return s
+	}
+	redacted := r.redactValue(path, content, decoded)
+	if equalJSON(redacted, decoded) {
+		return s
+	}
+	r.paths[pointerOf(path)] = true
+	text, err := json.Marshal(redacted)
+	if err != nil {
+		return redactedText
+	}
+	return string(text)
+}
+
+// redactValue returns value, an example of the field at path, with every
+// sensitive part replaced. Objects and arrays, such as root examples, are
+// redacted field by field following schema, and so is embedded JSON.
+func (r *redaction) redactValue(path []string, schema *Schema, value any) any {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			var childSchema *Schema
+			if schema != nil {
+				childSchema = schema.Properties[key]
+			}
+			redacted[key] = r.redactValue(append(path, key), childSchema, child)
+		}
+		return redacted
+	case []interface{}:
+		var itemSchema *Schema
+		if schema != nil {
+			itemSchema = schema.Items
+		}
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = r.redactValue(append(path, itemsSegment), itemSchema, item)
+		}
+		return redacted
+	case nil, bool:
+		return value
+	}
+
+	if r.isSensitive(path, schema, value) {
+		r.paths[pointerOf(path)] = true
+		return synthetic(schema, value)
+	}
+	if s, ok := value.(string); ok && schema != nil {
+		if content := embeddedContent(schema); content != nil {
+			return r.redactEmbedded(path, content, s)
+		}
+	}
+	return value
+}
+
+// synthetic returns a substitute for a sensitive value: a documentation-safe
+// value of the same format, a value matching an inferred pattern (such as
+// "000-00-0000" for ^[0-9]{3}-[0-9]{2}-[0-9]{4}$), or a masked value.
+func synthetic(schema *Schema, value any) any {
+	if _, ok := value.(string); !ok {
+		return 0.0
+	}
+	if schema != nil {
+		if s, ok := syntheticValues[schema.Format]; ok {
+			return s
+		}
+		if template, ok := parsePatternTemplate(schema.Pattern); ok {
+			return fillTemplate(template)
+		}
+	}
+	return redactedText
+}
+
+// fillTemplate returns the shortest string matching a pattern template.
+func fillTemplate(template []patternToken) string {
+	var b strings.Builder
+	for _, t := range template {
+		if t.literal != 0 {
+			b.WriteByte(t.literal)
+			continue
+		}
+		c := byte('x')
+		switch {
+		case t.classes&classDigit != 0:
+			c = '0'
+		case t.classes&classUpper != 0:
+			c = 'X'
+		}
+		b.WriteString(strings.Repeat(string(c), t.min))
+	}
+	return b.String()
+}
+
+// dedupExamples removes the duplicates that redaction may introduce.
+func dedupExamples(examples []any) []any {
+	var deduped []any
+	for _, example := range examples {
+		if !slices.ContainsFunc(deduped, func(e any) bool { return equalJSON(e, example) }) {
+			deduped = append(deduped, example)
+		}
+	}
+	return deduped
+}
+
+// equalJSON reports whether two decoded JSON values are equal.
+func equalJSON(a, b any) bool {
+	x, okA := sampleValue(a)
+	y, okB := sampleValue(b)
+	return okA && okB && x.key == y.key
+}
+
+// pointerOf formats a node path as a JSON Pointer, e.g. "/users/[]/email".
+func pointerOf(path []string) string {
+	var b strings.Builder
+	for _, segment := range path {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(segment))
+	}
+	return b.String()
+}
+
+// sortedPaths returns the recorded paths in order.
+func (r *redaction) sortedPaths() []string {
+	paths := make([]string, 0, len(r.paths))
+	for path := range r.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
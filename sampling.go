@@ -0,0 +1,161 @@
+package jsonschema
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// samplingKind identifies a sampling strategy.
+type samplingKind int
+
+const (
+	samplingAll samplingKind = iota
+	samplingReservoir
+	samplingEveryKth
+	samplingTimeBucketed
+	samplingStructureOnly
+)
+
+// SamplingStrategy selects which samples contribute to the schema when the
+// input is too large to observe in full. Unlike WithMaxSamples, which keeps only
+// the first N samples, the strategies spread their budget over the whole input.
+// Create one with Reservoir, EveryKth, TimeBucketed or StructureOnlyAfter and
+// pass it to WithSampling.
+type SamplingStrategy struct {
+	kind      samplingKind
+	size      int // reservoir size, per-bucket cap or value-tracking limit
+	every     int
+	width     time.Duration
+	timestamp func(interface{}) (time.Time, bool)
+}
+
+// Reservoir keeps a uniform random sample of size samples out of everything
+// added (Algorithm R), so late records are as likely to be represented as early
+// ones. The retained samples are held in memory and the schema is rebuilt from
+// them when requested.
+func Reservoir(size int) SamplingStrategy {
+	return SamplingStrategy{kind: samplingReservoir, size: size}
+}
+
+// EveryKth observes the 1st, (k+1)th, (2k+1)th... sample and ignores the others.
+func EveryKth(k int) SamplingStrategy {
+	return SamplingStrategy{kind: samplingEveryKth, every: k}
+}
+
+// TimeBucketed stratifies samples by time: timestamp extracts the time of a
+// sample, samples are grouped in buckets of the given width, and at most
+// perBucket samples are observed per bucket. Samples for which timestamp
+// reports false share a single bucket of their own; with a nil timestamp,
+// every sample is in that bucket.
+func TimeBucketed(width time.Duration, perBucket int, timestamp func(sample interface{}) (time.Time, bool)) SamplingStrategy {
+	return SamplingStrategy{kind: samplingTimeBucketed, width: width, size: perBucket, timestamp: timestamp}
+}
+
+// StructureOnlyAfter observes every sample, but after the first n samples only
+// records structure — types, fields and their presence — and stops tracking
+// values (examples, format detection, defaults). Required fields, types and
+// const stay exact over the whole input while the per-value cost is bounded.
+// Formats and the other value-derived keywords describe the values of the first
+// n samples; fields observed after them carry x-values-sampled, the number of
+// values those keywords were inferred from.
+func StructureOnlyAfter(n int) SamplingStrategy {
+	return SamplingStrategy{kind: samplingStructureOnly, size: n}
+}
+
+// samplerState is the per-generator state of the configured SamplingStrategy.
+type samplerState struct {
+	seen      int           // samples offered to the strategy
+	buckets   map[int64]int // TimeBucketed: samples observed per bucket
+	undated   int           // TimeBucketed: samples observed without a timestamp
+	reservoir []interface{} // Reservoir: retained samples
+	dirty     bool          // Reservoir: tree must be rebuilt from reservoir
+	base      *SchemaNode   // Reservoir: tree loaded with Load, merged into rebuilds
+	rng       *rand.Rand    // Reservoir: replacement decisions
+}
+
+// admitSample reports whether data should be observed according to the
+// sampling strategy. Reservoir sampling stores the sample itself and always
+// reports false. Must be called with g.mu held.
+func (g *Generator) admitSample(data interface{}) bool {
+	s := &g.sampler
+	s.seen++
+
+	switch g.sampling.kind {
+	case samplingReservoir:
+		g.addToReservoir(data)
+		return false
+
+	case samplingEveryKth:
+		return g.sampling.every <= 1 || (s.seen-1)%g.sampling.every == 0
+
+	case samplingTimeBucketed:
+		var ts time.Time
+		ok := false
+		if g.sampling.timestamp != nil {
+			ts, ok = g.sampling.timestamp(data)
+		}
+		if !ok {
+			if s.undated >= g.sampling.size {
+				return false
+			}
+			s.undated++
+			return true
+		}
+		if s.buckets == nil {
+			s.buckets = make(map[int64]int)
+		}
+		bucket := ts.Truncate(g.sampling.width).UnixNano()
+		if s.buckets[bucket] >= g.sampling.size {
+			return false
+		}
+		s.buckets[bucket]++
+		return true
+	}
+
+	return true
+}
+
+// structureOnlyNext reports whether the next admitted sample only has its
+// structure recorded (StructureOnlyAfter). Must be called with g.mu held.
+func (g *Generator) structureOnlyNext() bool {
+	return g.sampling.kind == samplingStructureOnly && g.sampleCount >= g.sampling.size
+}
+
+// addToReservoir offers data to the reservoir. Must be called with g.mu held.
+func (g *Generator) addToReservoir(data interface{}) {
+	s := &g.sampler
+	if len(s.reservoir) < g.sampling.size {
+		s.reservoir = append(s.reservoir, data)
+		g.sampleCount++
+	} else {
+		if s.rng == nil {
+			s.rng = rand.New(rand.NewPCG(g.seed, g.seed))
+		}
+		j := s.rng.IntN(s.seen)
+		if j >= g.sampling.size {
+			return
+		}
+		s.reservoir[j] = data
+	}
+	s.dirty = true
+	g.currentSchema = nil
+}
+
+// syncTree rebuilds the tree from the reservoir when reservoir sampling is in
+// use and the reservoir changed since the last rebuild. Must be called with g.mu held.
+func (g *Generator) syncTree() {
+	s := &g.sampler
+	if !s.dirty {
+		return
+	}
+
+	g.rootNode = NewSchemaNode()
+	if s.base != nil {
+		g.rootNode.merge(s.base)
+	}
+	opts := g.observeOptions()
+	for _, data := range s.reservoir {
+		g.observeRoot(data, &opts)
+	}
+	s.dirty = false
+}
@@ -0,0 +1,176 @@
+package jsonschema
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSamplingReservoir(t *testing.T) {
+	// The first records lack "late"; only records at the end have it. First-N
+	// sampling would never see it, a uniform reservoir almost surely does.
+	generator := New(WithSampling(Reservoir(50)))
+	for i := 0; i < 1000; i++ {
+		if i < 500 {
+			generator.AddSample(fmt.Sprintf(`{"id": %d}`, i))
+		} else {
+			generator.AddSample(fmt.Sprintf(`{"id": %d, "late": true}`, i))
+		}
+	}
+
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["late"] == nil {
+		t.Error("Expected reservoir to include late records")
+	}
+	if generator.sampleCount != 50 {
+		t.Errorf("Expected 50 retained samples, got %d", generator.sampleCount)
+	}
+
+	firstN := New(WithMaxSamples(50))
+	for i := 0; i < 1000; i++ {
+		firstN.AddSample(fmt.Sprintf(`{"id": %d, "late": %v}`, i, i >= 500))
+	}
+	if firstN.GetCurrentSchema().Properties["late"].Const != false {
+		t.Error("Expected first-N sampling to only see early records")
+	}
+}
+
+func TestSamplingReservoirDeterministic(t *testing.T) {
+	run := func(seed uint64) string {
+		generator := New(WithSampling(Reservoir(5)), WithSamplingSeed(seed))
+		for i := 0; i < 100; i++ {
+			generator.AddSample(fmt.Sprintf(`{"f%d": 1}`, i))
+		}
+		schemaJSON, err := generator.Generate()
+		if err != nil {
+			t.Fatalf("Failed to generate schema: %v", err)
+		}
+		return schemaJSON
+	}
+
+	if run(1) != run(1) {
+		t.Error("Expected identical schemas for the same seed")
+	}
+	if run(1) == run(2) {
+		t.Error("Expected different schemas for different seeds")
+	}
+}
+
+func TestSamplingReservoirAfterLoad(t *testing.T) {
+	generator := New(WithSampling(Reservoir(10)))
+	if err := generator.Load(`{"type": "object", "properties": {"loaded": {"type": "string"}}, "required": ["loaded"]}`); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	generator.AddSample(`{"added": 1}`)
+
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["loaded"] == nil || schema.Properties["added"] == nil {
+		t.Errorf("Expected loaded and added properties, got %v", schema.Properties)
+	}
+}
+
+func TestSamplingEveryKth(t *testing.T) {
+	generator := New(WithSampling(EveryKth(3)))
+	for i := 0; i < 9; i++ {
+		generator.AddSample(fmt.Sprintf(`{"n%d": %d}`, i, i))
+	}
+
+	schema := generator.GetCurrentSchema()
+	for _, key := range []string{"n0", "n3", "n6"} {
+		if schema.Properties[key] == nil {
+			t.Errorf("Expected %s to be observed", key)
+		}
+	}
+	if len(schema.Properties) != 3 {
+		t.Errorf("Expected 3 observed samples, got properties %v", schema.Properties)
+	}
+}
+
+func TestSamplingTimeBucketed(t *testing.T) {
+	timestamp := func(sample interface{}) (time.Time, bool) {
+		obj, _ := sample.(map[string]interface{})
+		s, _ := obj["ts"].(string)
+		ts, err := time.Parse(time.RFC3339, s)
+		return ts, err == nil
+	}
+	generator := New(WithSampling(TimeBucketed(24*time.Hour, 2, timestamp)))
+
+	// Ten records per day for three days: only two per day are observed.
+	for day := 1; day <= 3; day++ {
+		for i := 0; i < 10; i++ {
+			generator.AddSample(fmt.Sprintf(`{"ts": "2024-01-%02dT%02d:00:00Z", "day%d": %d}`, day, i, day, i))
+		}
+	}
+	generator.AddSample(`{"undated": true}`)
+
+	if generator.sampleCount != 7 {
+		t.Errorf("Expected 7 observed samples, got %d", generator.sampleCount)
+	}
+	schema := generator.GetCurrentSchema()
+	for _, key := range []string{"day1", "day2", "day3", "undated"} {
+		if schema.Properties[key] == nil {
+			t.Errorf("Expected %s to be observed", key)
+		}
+	}
+}
+
+func TestSamplingStructureOnlyAfter(t *testing.T) {
+	generator := New(WithSampling(StructureOnlyAfter(2)), WithExamples())
+	generator.AddSample(`{"email": "a@example.com", "kind": "x"}`)
+	generator.AddSample(`{"email": "b@example.com", "kind": "x"}`)
+	// Values are no longer tracked, so the format only describes the first
+	// samples; the missing "kind" still makes it optional.
+	generator.AddSample(`{"email": "not-an-email", "extra": "e"}`)
+
+	schema := generator.GetCurrentSchema()
+	email := schema.Properties["email"]
+	if email.Format != "email" || email.Extensions["x-values-sampled"] != 2 {
+		t.Errorf("Expected email format from the 2 sampled values, got %q, %v", email.Format, email.Extensions)
+	}
+	if email.Example != "a@example.com" {
+		t.Errorf("Expected example from the first samples, got %v", email.Example)
+	}
+	if schema.Properties["kind"].Const != "x" {
+		t.Errorf("Expected const x, every kind value was checked, got %v", schema.Properties["kind"].Const)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "email" {
+		t.Errorf("Expected only email to be required, got %v", schema.Required)
+	}
+	extra := schema.Properties["extra"]
+	if extra == nil || extra.Type != "string" || extra.Example != nil || extra.Extensions["x-values-sampled"] != 0 {
+		t.Errorf("Expected structure-only string for extra, got %+v", extra)
+	}
+}
+
+func TestSamplingStructureOnlyKeywords(t *testing.T) {
+	generator := New(WithSampling(StructureOnlyAfter(2)), WithExampleCount(2), WithDefaultInference(0.5))
+	generator.AddSample(`{"k": "x", "n": 10, "id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8", "s": "a"}`)
+	generator.AddSample(`{"k": "x", "n": 10, "id": "6ba7b811-9dad-11d1-80b4-00c04fd430c8", "s": "a"}`)
+	generator.AddSample(`{"k": "y", "n": 10, "id": "not-a-uuid", "s": "b"}`)
+
+	schema := generator.GetCurrentSchema()
+	// const is checked against every value.
+	if got := schema.Properties["k"].Const; got != nil {
+		t.Errorf("Expected no const for k, got %v", got)
+	}
+	if got := schema.Properties["n"].Const; got != 10.0 {
+		t.Errorf("Expected const 10 for n, got %v", got)
+	}
+	// format, default and examples come from the first 2 values.
+	if id := schema.Properties["id"]; id.Format != "uuid" || len(id.Examples) != 2 {
+		t.Errorf("Expected uuid format and 2 examples for id, got %+v", id)
+	}
+	if s := schema.Properties["s"]; s.Default != "a" || s.Extensions["x-values-sampled"] != 2 {
+		t.Errorf("Expected default a from 2 sampled values for s, got %+v", s)
+	}
+}
+
+func TestSamplingTimeBucketedNilTimestamp(t *testing.T) {
+	generator := New(WithSampling(TimeBucketed(time.Hour, 2, nil)))
+	for i := 0; i < 5; i++ {
+		generator.AddSample(fmt.Sprintf(`{"id": %d}`, i))
+	}
+	if generator.sampleCount != 2 {
+		t.Errorf("Expected 2 undated samples to be observed, got %d", generator.sampleCount)
+	}
+}
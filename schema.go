@@ -1,18 +1,41 @@
 package jsonschema
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
 
 // Schema represents a JSON Schema
 type Schema struct {
 	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
 	Type                 any                `json:"type,omitempty"` // can be string or []string
 	Properties           map[string]*Schema `json:"properties,omitempty"`
 	Items                *Schema            `json:"items,omitempty"`
 	Required             []string           `json:"required,omitempty"`
 	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MultipleOf           *float64           `json:"multipleOf,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
 	Const                any                `json:"const,omitempty"`
+	Default              any                `json:"default,omitempty"`
 	Example              any                `json:"example,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
 	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	ContentMediaType     string             `json:"contentMediaType,omitempty"`
+	ContentSchema        *Schema            `json:"contentSchema,omitempty"`
+
+	// Extensions holds "x-" annotation keywords, such as the x-format-mismatches
+	// count reported by WithFormatTolerance. They are written alongside the
+	// standard keywords and collected back when a schema is unmarshalled.
+	Extensions map[string]any `json:"-"`
 }
 
 // NewSchema creates a new Schema with default Draft 07 values.
@@ -37,9 +60,196 @@ func NewSchemaWithVersion(version SchemaVersion) *Schema {
 // MarshalJSON customizes JSON marshaling for Schema
 func (s *Schema) MarshalJSON() ([]byte, error) {
 	type Alias Schema
-	return json.Marshal(&struct {
+	data, err := json.Marshal(&struct {
 		*Alias
 	}{
 		Alias: (*Alias)(s),
 	})
+	if err != nil || len(s.Extensions) == 0 {
+		return data, err
+	}
+
+	// Append the extensions, in key order, to the standard keywords
+	ext, err := json.Marshal(s.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 2 { // "{}"
+		return ext, nil
+	}
+	data = append(data[:len(data)-1], ',')
+	return append(data, ext[1:]...), nil
+}
+
+// UnmarshalJSON customizes JSON unmarshaling for Schema, collecting "x-"
+// keywords into Extensions.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type Alias Schema
+	if err := json.Unmarshal(data, (*Alias)(s)); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for key, value := range raw {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+		var v any
+		if err := json.Unmarshal(value, &v); err != nil {
+			return err
+		}
+		if s.Extensions == nil {
+			s.Extensions = make(map[string]any)
+		}
+		s.Extensions[key] = v
+	}
+	return nil
+}
+
+// setExtension sets the "x-" annotation keyword key to value.
+func (s *Schema) setExtension(key string, value any) {
+	if s.Extensions == nil {
+		s.Extensions = make(map[string]any)
+	}
+	s.Extensions[key] = value
+}
+
+// clone returns a deep copy of s.
+func (s *Schema) clone() *Schema {
+	if s == nil {
+		return nil
+	}
+
+	c := *s
+	if types, ok := s.Type.([]string); ok {
+		c.Type = append([]string{}, types...)
+	}
+	if s.Properties != nil {
+		c.Properties = make(map[string]*Schema, len(s.Properties))
+		for key, prop := range s.Properties {
+			c.Properties[key] = prop.clone()
+		}
+	}
+	c.Items = s.Items.clone()
+	c.ContentSchema = s.ContentSchema.clone()
+	c.Required = append([]string(nil), s.Required...)
+	c.Enum = append([]any(nil), s.Enum...)
+	c.Examples = append([]any(nil), s.Examples...)
+	if s.MultipleOf != nil {
+		step := *s.MultipleOf
+		c.MultipleOf = &step
+	}
+	if s.AdditionalProperties != nil {
+		ap := *s.AdditionalProperties
+		c.AdditionalProperties = &ap
+	}
+	if s.Extensions != nil {
+		c.Extensions = maps.Clone(s.Extensions)
+	}
+	return &c
+}
+
+// overlay copies every keyword set in override onto s. Properties and items are
+// overlaid recursively and required lists are combined. If override sets a type
+// different from the one in s, the keywords inferred for the old type no longer
+// apply and s is cleared first.
+func (s *Schema) overlay(override *Schema) {
+	if override.Type != nil && !reflect.DeepEqual(override.Type, s.Type) {
+		*s = Schema{Schema: s.Schema}
+	}
+
+	if override.Schema != "" {
+		s.Schema = override.Schema
+	}
+	if override.Ref != "" {
+		s.Ref = override.Ref
+	}
+	if override.Title != "" {
+		s.Title = override.Title
+	}
+	if override.Description != "" {
+		s.Description = override.Description
+	}
+	if override.Type != nil {
+		s.Type = override.Type
+	}
+	if override.Format != "" {
+		s.Format = override.Format
+	}
+	if override.Pattern != "" {
+		s.Pattern = override.Pattern
+	}
+	if override.MultipleOf != nil {
+		step := *override.MultipleOf
+		s.MultipleOf = &step
+	}
+	if override.Enum != nil {
+		s.Enum = append([]any(nil), override.Enum...)
+		// An inferred const may contradict the enum; the enum is authoritative.
+		s.Const = nil
+	}
+	if override.Const != nil {
+		s.Const = override.Const
+	}
+	if override.Default != nil {
+		s.Default = override.Default
+	}
+	if override.Example != nil {
+		s.Example = override.Example
+	}
+	if override.Examples != nil {
+		s.Examples = append([]any(nil), override.Examples...)
+	}
+	if override.AdditionalProperties != nil {
+		ap := *override.AdditionalProperties
+		s.AdditionalProperties = &ap
+	}
+	for key, value := range override.Extensions {
+		s.setExtension(key, value)
+	}
+
+	if override.ContentEncoding != "" {
+		s.ContentEncoding = override.ContentEncoding
+	}
+	if override.ContentMediaType != "" {
+		s.ContentMediaType = override.ContentMediaType
+	}
+	if override.ContentSchema != nil {
+		if s.ContentSchema == nil {
+			s.ContentSchema = override.ContentSchema.clone()
+		} else {
+			s.ContentSchema.overlay(override.ContentSchema)
+		}
+	}
+
+	if override.Items != nil {
+		if s.Items == nil {
+			s.Items = override.Items.clone()
+		} else {
+			s.Items.overlay(override.Items)
+		}
+	}
+	for key, prop := range override.Properties {
+		if s.Properties == nil {
+			s.Properties = make(map[string]*Schema)
+		}
+		if existing, ok := s.Properties[key]; ok {
+			existing.overlay(prop)
+		} else {
+			s.Properties[key] = prop.clone()
+		}
+	}
+	if len(override.Required) > 0 {
+		required := append([]string(nil), s.Required...)
+		for _, name := range override.Required {
+			if !slices.Contains(required, name) {
+				required = append(required, name)
+			}
+		}
+		sort.Strings(required)
+		s.Required = required
+	}
 }
@@ -0,0 +1,404 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// distinctSketchSize is the number of hashes kept by a distinctSketch; the
+// estimate is exact below it and within about 13% (1/sqrt(k)) above it.
+const distinctSketchSize = 64
+
+// distinctSketch estimates the number of distinct values of a node with a
+// k-minimum-values sketch: it keeps the distinctSketchSize smallest hashes of
+// the values seen. Sketches of disjoint shards merge without loss.
+type distinctSketch struct {
+	hashes []uint64 // sorted, distinct
+}
+
+// add records the hash of one value.
+func (s *distinctSketch) add(hash uint64) {
+	i, found := slices.BinarySearch(s.hashes, hash)
+	if found || i >= distinctSketchSize {
+		return
+	}
+	s.hashes = slices.Insert(s.hashes, i, hash)
+	if len(s.hashes) > distinctSketchSize {
+		s.hashes = s.hashes[:distinctSketchSize]
+	}
+}
+
+// merge folds the hashes of other into s.
+func (s *distinctSketch) merge(other *distinctSketch) {
+	for _, hash := range other.hashes {
+		s.add(hash)
+	}
+}
+
+// estimate returns the estimated number of distinct values.
+func (s *distinctSketch) estimate() int {
+	if len(s.hashes) < distinctSketchSize {
+		return len(s.hashes)
+	}
+	kth := float64(s.hashes[distinctSketchSize-1]) / math.MaxUint64
+	return int(math.Round(float64(distinctSketchSize-1) / kth))
+}
+
+// hashValue hashes a primitive value, keeping values of different types apart.
+func hashValue(value any) (uint64, bool) {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = "s" + v
+	case float64:
+		text = "n" + strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		text = "b" + strconv.FormatBool(v)
+	default:
+		return 0, false
+	}
+	// FNV-1a, then a finaliser so that the hashes spread over the whole range
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(text); i++ {
+		h ^= uint64(text[i])
+		h *= 1099511628211
+	}
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	return h, true
+}
+
+// valueStats holds the counters of WithStats.
+type valueStats struct {
+	min, max       float64 // numbers
+	numbers        bool    // min and max are set
+	minLen, maxLen int     // strings, in characters
+	strings        bool    // minLen and maxLen are set
+	distinct       distinctSketch
+}
+
+// observeStats records a primitive value for WithStats.
+func (n *SchemaNode) observeStats(value any) {
+	s := &n.stats
+	switch v := value.(type) {
+	case float64:
+		if !s.numbers || v < s.min {
+			s.min = v
+		}
+		if !s.numbers || v > s.max {
+			s.max = v
+		}
+		s.numbers = true
+	case string:
+		length := utf8.RuneCountInString(v)
+		if !s.strings || length < s.minLen {
+			s.minLen = length
+		}
+		if !s.strings || length > s.maxLen {
+			s.maxLen = length
+		}
+		s.strings = true
+	}
+	if hash, ok := hashValue(value); ok {
+		s.distinct.add(hash)
+	}
+}
+
+// mergeStats folds the WithStats counters of other into n.
+func (n *SchemaNode) mergeStats(other *SchemaNode) {
+	s, o := &n.stats, &other.stats
+	if o.numbers {
+		if !s.numbers || o.min < s.min {
+			s.min = o.min
+		}
+		if !s.numbers || o.max > s.max {
+			s.max = o.max
+		}
+		s.numbers = true
+	}
+	if o.strings {
+		if !s.strings || o.minLen < s.minLen {
+			s.minLen = o.minLen
+		}
+		if !s.strings || o.maxLen > s.maxLen {
+			s.maxLen = o.maxLen
+		}
+		s.strings = true
+	}
+	s.distinct.merge(&o.distinct)
+}
+
+// Stats describes the values observed for every field of the samples.
+type Stats struct {
+	// Samples is the number of samples observed.
+	Samples int `json:"samples"`
+	// Fields holds one entry per field, in path order.
+	Fields []FieldStats `json:"fields"`
+}
+
+// FieldStats describes the values observed for one field.
+type FieldStats struct {
+	// Path is the JSON Pointer of the field, with "[]" for array items,
+	// e.g. "/users/[]/email". The root is "".
+	Path string `json:"path"`
+	// Count is the number of times the field was present, null included.
+	Count int `json:"count"`
+	// Presence is Count divided by the number of objects holding the field;
+	// it is 1 for the root and for array items.
+	Presence float64 `json:"presence"`
+	// NullCount is the number of null values.
+	NullCount int `json:"nullCount"`
+	// Types counts the values of each JSON type, null included.
+	Types map[string]int `json:"types"`
+	// Formats gives, for each format still matching, the share of strings
+	// it matched: always 1 without WithFormatTolerance.
+	Formats map[string]float64 `json:"formats,omitempty"`
+
+	// The following are only recorded with WithStats.
+
+	// Min and Max are the smallest and largest numbers.
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// MinLength and MaxLength are the shortest and longest strings, in characters.
+	MinLength *int `json:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty"`
+	// Distinct estimates the number of distinct primitive values. It is exact
+	// up to 63 values and within about 13% beyond.
+	Distinct *int `json:"distinct,omitempty"`
+}
+
+// Stats returns statistics about every field of the samples observed: how often
+// it was present and null, which types and formats its values had and, with
+// WithStats, the range of its values and an estimate of their distinct count.
+// After Load the loaded fields count as seen once.
+// Thread-safe: can be called concurrently from multiple goroutines.
+func (g *Generator) Stats() *Stats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.syncTree()
+	stats := &Stats{Samples: g.sampleCount}
+	g.rootNode.collectStats(stats, nil, g.rootNode.sampleCount, g.statsEnabled)
+	sort.Slice(stats.Fields, func(i, j int) bool {
+		return stats.Fields[i].Path < stats.Fields[j].Path
+	})
+	return stats
+}
+
+// collectStats appends the statistics of n and its descendants to stats.
+// holders is the number of objects or arrays n was looked up in.
+func (n *SchemaNode) collectStats(stats *Stats, path []string, holders int, detailed bool) {
+	if n.ignore != ignoreNone {
+		return
+	}
+
+	fs := FieldStats{
+		Path:      pointerOf(path),
+		Count:     n.sampleCount + n.nullCount,
+		Presence:  1,
+		NullCount: n.nullCount,
+		Types:     make(map[string]int, len(n.observedTypes)+1),
+	}
+	for typ, count := range n.observedTypes {
+		fs.Types[typ] = count
+	}
+	if n.nullCount > 0 {
+		fs.Types["null"] += n.nullCount
+	}
+	if len(path) > 0 && path[len(path)-1] != itemsSegment && holders > 0 {
+		fs.Presence = float64(fs.Count) / float64(holders)
+	}
+	if n.stringCount > 0 && len(n.candidateFormats) > 0 {
+		fs.Formats = make(map[string]float64, len(n.candidateFormats))
+		for i, f := range n.candidateFormats {
+			ratio := 1.0
+			if n.formatMatches != nil {
+				ratio = float64(n.formatMatches[i]) / float64(n.stringCount)
+			}
+			if ratio > 0 {
+				fs.Formats[f.Name] = ratio
+			}
+		}
+	}
+	if detailed {
+		// Copies, so that the report does not change with later samples
+		s := n.stats
+		if s.numbers {
+			fs.Min, fs.Max = &s.min, &s.max
+		}
+		if s.strings {
+			fs.MinLength, fs.MaxLength = &s.minLen, &s.maxLen
+		}
+		if len(s.distinct.hashes) > 0 {
+			distinct := s.distinct.estimate()
+			fs.Distinct = &distinct
+		}
+	}
+	stats.Fields = append(stats.Fields, fs)
+	n.collectChildStats(stats, path, detailed)
+}
+
+// collectChildStats collects the statistics of the children of n. Like
+// walkChildren, the content of JSON embedded in a string shares its path.
+func (n *SchemaNode) collectChildStats(stats *Stats, path []string, detailed bool) {
+	if n.embedded != nil {
+		n.embedded.collectChildStats(stats, path, detailed)
+	}
+	if n.arrayItemNode != nil {
+		n.arrayItemNode.collectStats(stats, append(path, itemsSegment), n.observedTypes["array"], detailed)
+	}
+	for key, child := range n.objectProperties {
+		child.collectStats(stats, append(path, key), n.observedTypes["object"], detailed)
+	}
+}
+
+// WriteJSON writes the statistics to w as indented JSON.
+func (s *Stats) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteTable writes the statistics to w as a human-readable table, one line
+// per field:
+//
+//	PATH     PRESENT  NULLS  TYPES               FORMATS     RANGE  LENGTH  DISTINCT
+//	/email   100.0%   0      string:3            email 100%  -      9..15   3
+func (s *Stats) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "PATH\tPRESENT\tNULLS\tTYPES\tFORMATS\tRANGE\tLENGTH\tDISTINCT\n")
+	for _, f := range s.Fields {
+		path := f.Path
+		if path == "" {
+			path = "/"
+		}
+		fmt.Fprintf(tw, "%s\t%.1f%%\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			path, 100*f.Presence, f.NullCount, typeHistogram(f.Types), formatRatios(f.Formats),
+			valueRange(f.Min, f.Max), lengthRange(f.MinLength, f.MaxLength), optionalInt(f.Distinct))
+	}
+	return tw.Flush()
+}
+
+// typeHistogram renders type counts as "integer:3 null:1", by type name.
+func typeHistogram(types map[string]int) string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + ":" + strconv.Itoa(types[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatRatios renders format match ratios as "email 100%", by format name.
+func formatRatios(formats map[string]float64) string {
+	if len(formats) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %.0f%%", name, 100*formats[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+// valueRange renders a numeric range as "min..max", or "-" when there is none.
+func valueRange(min, max *float64) string {
+	if min == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*min, 'g', -1, 64) + ".." + strconv.FormatFloat(*max, 'g', -1, 64)
+}
+
+// lengthRange renders a string length range as "min..max", or "-" when there is none.
+func lengthRange(min, max *int) string {
+	if min == nil {
+		return "-"
+	}
+	return strconv.Itoa(*min) + ".." + strconv.Itoa(*max)
+}
+
+// optionalInt renders v, or "-" when it is not set.
+func optionalInt(v *int) string {
+	if v == nil {
+		return "-"
+	}
+	return strconv.Itoa(*v)
+}
+
+// annotateStats adds the WithStatsAnnotations keywords of n to schema: the
+// number of values observed, their count per type and the number of nulls.
+func (n *SchemaNode) annotateStats(schema *Schema) {
+	schema.setExtension("x-sample-count", n.sampleCount)
+	types := make(map[string]int, len(n.observedTypes))
+	for typ, count := range n.observedTypes {
+		types[typ] = count
+	}
+	schema.setExtension("x-type-counts", types)
+	schema.setExtension("x-null-count", n.nullCount)
+}
+
+// occurrence returns the share of holders objects in which n was present,
+// null included, rounded to four decimal places.
+func (n *SchemaNode) occurrence(holders int) float64 {
+	if holders == 0 {
+		return 0
+	}
+	return math.Round(1e4*float64(n.sampleCount+n.nullCount)/float64(holders)) / 1e4
+}
+
+// loadedStatsAnnotations restores the counts written by WithStatsAnnotations,
+// if schema has them. It reports whether it did.
+func (n *SchemaNode) loadedStatsAnnotations(schema *Schema) bool {
+	count, ok := extensionCount(schema, "x-sample-count")
+	if !ok {
+		return false
+	}
+	n.sampleCount = count
+	if types, ok := schema.Extensions["x-type-counts"].(map[string]any); ok {
+		n.observedTypes = make(map[string]int, len(types))
+		for typ, v := range types {
+			if c, ok := v.(float64); ok && c > 0 {
+				n.observedTypes[typ] = int(c)
+			}
+		}
+	}
+	n.nullCount, _ = extensionCount(schema, "x-null-count")
+
+	// With the values counted, a missing const means that they differed
+	if count > 0 {
+		if schema.Const != nil {
+			n.constValue, n.constSet = schema.Const, true
+		} else {
+			n.constDiffer = true
+		}
+	}
+	return true
+}
+
+// extensionCount returns the non-negative integer stored in the "x-"
+// keyword key of schema.
+func extensionCount(schema *Schema, key string) (int, bool) {
+	v, ok := schema.Extensions[key].(float64)
+	if !ok || v < 0 || v != math.Trunc(v) {
+		return 0, false
+	}
+	return int(v), true
+}
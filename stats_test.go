@@ -0,0 +1,198 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func statsByPath(stats *Stats) map[string]FieldStats {
+	fields := make(map[string]FieldStats, len(stats.Fields))
+	for _, f := range stats.Fields {
+		fields[f.Path] = f
+	}
+	return fields
+}
+
+func TestStats(t *testing.T) {
+	generator := New()
+	generator.AddSample(`{"id": 1, "email": "a@example.com", "note": null, "tags": ["x", "y"]}`)
+	generator.AddSample(`{"id": 2, "email": "b@example.com", "note": "hi", "tags": []}`)
+	generator.AddSample(`{"id": "3", "email": "c@example.com"}`)
+	generator.AddSample(`{"id": 4, "email": "d@example.com", "tags": ["x"]}`)
+
+	stats := generator.Stats()
+	if stats.Samples != 4 {
+		t.Errorf("Expected 4 samples, got %d", stats.Samples)
+	}
+	fields := statsByPath(stats)
+
+	id := fields["/id"]
+	if id.Count != 4 || id.Presence != 1 || id.Types["integer"] != 3 || id.Types["string"] != 1 {
+		t.Errorf("Unexpected id stats %+v", id)
+	}
+	note := fields["/note"]
+	if note.Count != 2 || note.Presence != 0.5 || note.NullCount != 1 || note.Types["null"] != 1 || note.Types["string"] != 1 {
+		t.Errorf("Unexpected note stats %+v", note)
+	}
+	if email := fields["/email"]; email.Formats["email"] != 1 {
+		t.Errorf("Expected email format ratio 1, got %+v", email)
+	}
+	if items := fields["/tags/[]"]; items.Count != 3 || items.Presence != 1 {
+		t.Errorf("Unexpected tags items stats %+v", items)
+	}
+	if root := fields[""]; root.Count != 4 || root.Types["object"] != 4 {
+		t.Errorf("Unexpected root stats %+v", root)
+	}
+	if id.Min != nil || id.Distinct != nil {
+		t.Errorf("Expected no value statistics without WithStats, got %+v", id)
+	}
+}
+
+func TestStatsValues(t *testing.T) {
+	generator := New(WithStats(), WithFormatTolerance(0.5))
+	for i := 0; i < 1000; i++ {
+		email := fmt.Sprintf("user%d@example.com", i%10)
+		if i%100 == 0 {
+			email = "n/a"
+		}
+		generator.AddSample(fmt.Sprintf(`{"n": %d, "user": %q, "email": %q}`, i, fmt.Sprint("u", i%200), email))
+	}
+
+	fields := statsByPath(generator.Stats())
+	n := fields["/n"]
+	if n.Min == nil || *n.Min != 0 || *n.Max != 999 {
+		t.Errorf("Expected range 0..999, got %+v", n)
+	}
+	if n.Distinct == nil || *n.Distinct < 850 || *n.Distinct > 1150 {
+		t.Errorf("Expected about 1000 distinct values, got %v", *n.Distinct)
+	}
+	user := fields["/user"]
+	if user.MinLength == nil || *user.MinLength != 2 || *user.MaxLength != 4 {
+		t.Errorf("Expected lengths 2..4, got %+v", user)
+	}
+	if user.Distinct == nil || *user.Distinct < 170 || *user.Distinct > 230 {
+		t.Errorf("Expected about 200 distinct users, got %v", *user.Distinct)
+	}
+	if email := fields["/email"]; *email.Distinct != 11 || email.Formats["email"] != 0.99 {
+		t.Errorf("Expected 11 distinct emails matching 99%%, got %+v", email)
+	}
+}
+
+func TestStatsParallel(t *testing.T) {
+	sequential := New(WithStats())
+	parallel := NewParallel(4, WithStats())
+	for i := 0; i < 400; i++ {
+		sample := fmt.Sprintf(`{"n": %d, "s": "v%d", "opt": null}`, i%150, i%37)
+		if i%3 == 0 {
+			sample = fmt.Sprintf(`{"n": %d, "s": "v%d"}`, i%150, i%37)
+		}
+		sequential.AddSample(sample)
+		parallel.AddSample(sample)
+	}
+
+	var want, got bytes.Buffer
+	if err := sequential.Stats().WriteJSON(&want); err != nil {
+		t.Fatal(err)
+	}
+	if err := parallel.Stats().WriteJSON(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("Parallel stats differ from sequential\ngot:  %s\nwant: %s", got.String(), want.String())
+	}
+}
+
+func TestStatsRenderers(t *testing.T) {
+	generator := New(WithStats())
+	generator.AddSample(`{"email": "a@example.com", "age": 30}`)
+	generator.AddSample(`{"email": "bob@example.com", "age": null}`)
+
+	var buf bytes.Buffer
+	if err := generator.Stats().WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Stats
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON stats: %v\n%s", err, buf.String())
+	}
+	if decoded.Samples != 2 || len(decoded.Fields) != 3 {
+		t.Errorf("Unexpected decoded stats %+v", decoded)
+	}
+
+	buf.Reset()
+	if err := generator.Stats().WriteTable(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "PATH") {
+		t.Fatalf("Unexpected table:\n%s", buf.String())
+	}
+	for _, want := range []string{"/age", "100.0%", "integer:1 null:1", "30..30", "/email", "email 100%", "13..15"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in table:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestStatsAnnotations(t *testing.T) {
+	generator := New(WithStatsAnnotations())
+	generator.AddSample(`{"id": 1, "note": null, "tags": ["a", null]}`)
+	generator.AddSample(`{"id": "2", "note": "hi"}`)
+	generator.AddSample(`{"id": 3}`)
+
+	schemaJSON, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	for _, want := range []string{
+		`"id":{"type":["integer","string"],"x-null-count":0,"x-occurrence":1,"x-sample-count":3,"x-type-counts":{"integer":2,"string":1}}`,
+		`"note":{"type":"string","const":"hi","x-null-count":1,"x-occurrence":0.6667,"x-sample-count":1,"x-type-counts":{"string":1}}`,
+		`"items":{"type":"string","const":"a","x-null-count":0,"x-sample-count":2,"x-type-counts":{"null":1,"string":1}}`,
+		`"x-sample-count":3,"x-type-counts":{"object":3}}`,
+	} {
+		if !strings.Contains(schemaJSON, want) {
+			t.Errorf("Expected %s in %s", want, schemaJSON)
+		}
+	}
+
+	plain, _ := New().Generate()
+	if strings.Contains(plain, "x-sample-count") {
+		t.Errorf("Expected no annotations by default, got %s", plain)
+	}
+}
+
+func TestStatsAnnotationsLoad(t *testing.T) {
+	generator := New(WithStatsAnnotations(), WithFormatTolerance(0.6))
+	generator.AddSample(`{"email": "a@example.com", "nick": "al"}`)
+	generator.AddSample(`{"email": "b@example.com", "nick": "bo"}`)
+	generator.AddSample(`{"email": "c@example.com", "nick": "cy"}`)
+	generator.AddSample(`{"email": "n/a"}`)
+	saved, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	restored := New(WithStatsAnnotations(), WithFormatTolerance(0.6))
+	if err := restored.Load(saved); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	restored.AddSample(`{"email": "unknown", "nick": "di"}`)
+
+	schema := restored.GetCurrentSchema()
+	if len(schema.Required) != 1 || schema.Required[0] != "email" {
+		t.Errorf("Expected nick to stay optional (4 of 5 samples), got required %v", schema.Required)
+	}
+	if got := schema.Extensions["x-sample-count"]; got != 5 {
+		t.Errorf("Expected 5 samples after load, got %v", got)
+	}
+	email := schema.Properties["email"]
+	if email.Format != "email" || email.Const != nil || email.Extensions["x-format-mismatches"] != 2 {
+		t.Errorf("Expected email with 2 of 5 mismatches, got %+v", email)
+	}
+	if stats := statsByPath(restored.Stats()); stats["/nick"].Count != 4 {
+		t.Errorf("Expected restored nick count 4, got %+v", stats["/nick"])
+	}
+}
@@ -0,0 +1,149 @@
+package jsonschema
+
+import (
+	"strconv"
+	"time"
+)
+
+// timestampKind is a non-standard timestamp representation recognised by
+// WithTimestampDetection: either a Go time layout for string values, or an
+// epoch unit for numbers and numeric strings.
+type timestampKind struct {
+	layout string  // Go time layout, for string timestamps
+	unit   string  // epoch unit: "s", "ms", "us" or "ns"
+	scale  float64 // epoch units per second
+}
+
+// Epoch values are only recognised between 2000-01-01 and 2100-01-01, which
+// keeps the ranges of the different units apart and rules out small counters.
+const (
+	minEpochSeconds = 946684800
+	maxEpochSeconds = 4102444800
+)
+
+// builtInTimestampKinds are the representations tried on every value. RFC 3339
+// timestamps are left to the date-time format.
+var builtInTimestampKinds = []timestampKind{
+	{layout: time.DateTime},                // 2006-01-02 15:04:05
+	{layout: "2006-01-02T15:04:05"},        // ISO 8601 without offset
+	{layout: time.RFC1123},                 // Mon, 02 Jan 2006 15:04:05 MST
+	{layout: time.RFC1123Z},                // Mon, 02 Jan 2006 15:04:05 -0700
+	{layout: time.RFC850},                  // Monday, 02-Jan-06 15:04:05 MST
+	{layout: time.ANSIC},                   // Mon Jan _2 15:04:05 2006
+	{layout: time.UnixDate},                // Mon Jan _2 15:04:05 MST 2006
+	{layout: "02/Jan/2006:15:04:05 -0700"}, // Apache common log format
+	{unit: "s", scale: 1},
+	{unit: "ms", scale: 1e3},
+	{unit: "us", scale: 1e6},
+	{unit: "ns", scale: 1e9},
+}
+
+// matches reports whether value is a timestamp of this kind.
+func (k timestampKind) matches(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		if k.layout != "" {
+			_, err := time.Parse(k.layout, v)
+			return err == nil
+		}
+		if !isDecimal(v) {
+			return false
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		return err == nil && k.inEpochRange(f)
+	case float64:
+		return k.unit != "" && k.inEpochRange(v)
+	}
+	return false
+}
+
+// inEpochRange reports whether f, in the unit of k, falls in the recognised range.
+func (k timestampKind) inEpochRange(f float64) bool {
+	seconds := f / k.scale
+	return seconds >= minEpochSeconds && seconds < maxEpochSeconds
+}
+
+// isDecimal reports whether s is made of digits with at most one decimal point.
+func isDecimal(s string) bool {
+	if s == "" {
+		return false
+	}
+	dot := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] >= '0' && s[i] <= '9':
+		case s[i] == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// observeTimestamp eliminates the timestamp kinds that value does not match.
+func (n *SchemaNode) observeTimestamp(value interface{}) {
+	if n.timestampKinds == nil {
+		n.timestampKinds = append([]timestampKind{}, builtInTimestampKinds...)
+	}
+	j := 0
+	for _, k := range n.timestampKinds {
+		if k.matches(value) {
+			n.timestampKinds[j] = k
+			j++
+		}
+	}
+	n.timestampKinds = n.timestampKinds[:j]
+}
+
+// mergeTimestamps keeps the timestamp kinds matched by the values of both nodes.
+func (n *SchemaNode) mergeTimestamps(other *SchemaNode) {
+	if other.timestampKinds == nil {
+		return
+	}
+	if n.timestampKinds == nil {
+		n.timestampKinds = append([]timestampKind{}, other.timestampKinds...)
+		return
+	}
+	j := 0
+	for _, k := range n.timestampKinds {
+		for _, otherKind := range other.timestampKinds {
+			if k == otherKind {
+				n.timestampKinds[j] = k
+				j++
+				break
+			}
+		}
+	}
+	n.timestampKinds = n.timestampKinds[:j]
+}
+
+// applyTimestamp annotates the schema with the timestamp kind matched by every
+// value, as x-timestamp-layout or x-epoch-unit.
+func (n *SchemaNode) applyTimestamp(schema *Schema) {
+	if len(n.timestampKinds) == 0 {
+		return
+	}
+	k := n.timestampKinds[0]
+	if k.layout != "" {
+		schema.setExtension("x-timestamp-layout", k.layout)
+	} else {
+		schema.setExtension("x-epoch-unit", k.unit)
+	}
+}
+
+// loadedTimestamp returns the timestamp kind recorded in a loaded schema, if any.
+// Layouts are accepted as they are, so that any Go layout survives a round trip.
+func loadedTimestamp(schema *Schema) (timestampKind, bool) {
+	if layout, ok := schema.Extensions["x-timestamp-layout"].(string); ok && layout != "" {
+		return timestampKind{layout: layout}, true
+	}
+	if unit, ok := schema.Extensions["x-epoch-unit"].(string); ok {
+		for _, k := range builtInTimestampKinds {
+			if k.unit == unit {
+				return k, true
+			}
+		}
+	}
+	return timestampKind{}, false
+}
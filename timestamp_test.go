@@ -0,0 +1,115 @@
+package jsonschema
+
+import "testing"
+
+func TestTimestampDetection(t *testing.T) {
+	generator := New(WithTimestampDetection())
+	generator.AddSample(`{
+		"sql": "2023-01-15 10:30:00",
+		"local": "2023-01-15T10:30:00",
+		"http": "Sun, 15 Jan 2023 10:30:00 GMT",
+		"apache": "15/Jan/2023:10:30:00 +0100",
+		"secs": 1673778600,
+		"millis": 1673778600123,
+		"micros": "1673778600123456",
+		"float": 1673778600.25,
+		"rfc3339": "2023-01-15T10:30:00Z",
+		"count": 42
+	}`)
+	generator.AddSample(`{
+		"sql": "2024-02-29 23:59:59.5",
+		"local": "2024-02-29T23:59:59",
+		"http": "Thu, 29 Feb 2024 23:59:59 GMT",
+		"apache": "29/Feb/2024:23:59:59 -0500",
+		"secs": "1709251199",
+		"millis": 1709251199000,
+		"micros": "1709251199000000",
+		"float": 1709251199,
+		"rfc3339": "2024-02-29T23:59:59Z",
+		"count": 43
+	}`)
+
+	schema := generator.GetCurrentSchema()
+	layouts := map[string]string{
+		"sql":    "2006-01-02 15:04:05",
+		"local":  "2006-01-02T15:04:05",
+		"http":   "Mon, 02 Jan 2006 15:04:05 MST",
+		"apache": "02/Jan/2006:15:04:05 -0700",
+	}
+	for field, layout := range layouts {
+		if got := schema.Properties[field].Extensions["x-timestamp-layout"]; got != layout {
+			t.Errorf("Expected %s layout %q, got %v", field, layout, got)
+		}
+	}
+	units := map[string]string{"secs": "s", "millis": "ms", "micros": "us", "float": "s"}
+	for field, unit := range units {
+		if got := schema.Properties[field].Extensions["x-epoch-unit"]; got != unit {
+			t.Errorf("Expected %s epoch unit %q, got %v", field, unit, got)
+		}
+	}
+	for _, field := range []string{"rfc3339", "count"} {
+		if ext := schema.Properties[field].Extensions; ext != nil {
+			t.Errorf("Expected no timestamp annotation on %s, got %v", field, ext)
+		}
+	}
+	if schema.Properties["rfc3339"].Format != "date-time" {
+		t.Errorf("Expected RFC 3339 values to keep the date-time format, got %q", schema.Properties["rfc3339"].Format)
+	}
+}
+
+func TestTimestampDetectionDisabled(t *testing.T) {
+	generator := New()
+	generator.AddSample(`{"sql": "2023-01-15 10:30:00", "secs": 1673778600}`)
+
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["sql"].Extensions != nil || schema.Properties["secs"].Extensions != nil {
+		t.Error("Expected no timestamp annotations without WithTimestampDetection")
+	}
+}
+
+func TestTimestampDetectionMismatch(t *testing.T) {
+	generator := New(WithTimestampDetection())
+	generator.AddSample(`{"ts": 1673778600}`)
+	generator.AddSample(`{"ts": 1673778600000}`)
+
+	if ext := generator.GetCurrentSchema().Properties["ts"].Extensions; ext != nil {
+		t.Errorf("Expected mixed units to yield no annotation, got %v", ext)
+	}
+}
+
+func TestTimestampDetectionLoad(t *testing.T) {
+	generator := New(WithTimestampDetection())
+	generator.AddSample(`{"ts": "15.01.2023 10:30", "sql": "2023-01-15 10:30:00"}`)
+	err := generator.Load(`{"type": "object", "properties": {
+		"ts": {"type": "string", "x-timestamp-layout": "02.01.2006 15:04"},
+		"ms": {"type": "integer", "x-epoch-unit": "ms"}
+	}, "required": ["ts", "ms"]}`)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	generator.AddSample(`{"ts": "29.02.2024 23:59", "ms": 1709251199000}`)
+
+	schema := generator.GetCurrentSchema()
+	if got := schema.Properties["ts"].Extensions["x-timestamp-layout"]; got != "02.01.2006 15:04" {
+		t.Errorf("Expected loaded layout to survive, got %v", got)
+	}
+	if got := schema.Properties["ms"].Extensions["x-epoch-unit"]; got != "ms" {
+		t.Errorf("Expected loaded epoch unit to survive, got %v", got)
+	}
+
+	generator.AddSample(`{"ts": "soon", "ms": 1}`)
+	schema = generator.GetCurrentSchema()
+	if schema.Properties["ts"].Extensions != nil || schema.Properties["ms"].Extensions != nil {
+		t.Error("Expected non-matching values to remove the loaded annotations")
+	}
+}
+
+func TestTimestampDetectionParallel(t *testing.T) {
+	parallel := NewParallel(4, WithTimestampDetection())
+	for i := 0; i < 100; i++ {
+		parallel.AddSample(`{"ts": "2023-01-15 10:30:00"}`)
+	}
+	if got := parallel.GetCurrentSchema().Properties["ts"].Extensions["x-timestamp-layout"]; got != "2006-01-02 15:04:05" {
+		t.Errorf("Expected merged layout, got %v", got)
+	}
+}
@@ -0,0 +1,129 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// addEmails adds n samples with an email field, the bad-th of which is not an email.
+func addEmails(t *testing.T, g interface{ AddSample(string) error }, n, bad int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		if i == bad {
+			email = "n/a"
+		}
+		if err := g.AddSample(fmt.Sprintf(`{"email": %q}`, email)); err != nil {
+			t.Fatalf("Failed to add sample: %v", err)
+		}
+	}
+}
+
+func TestFormatTolerance(t *testing.T) {
+	strict := New()
+	addEmails(t, strict, 1000, 500)
+	if format := strict.GetCurrentSchema().Properties["email"].Format; format != "" {
+		t.Errorf("Expected strict detection to drop the format, got %q", format)
+	}
+
+	tolerant := New(WithFormatTolerance(0.99))
+	addEmails(t, tolerant, 1000, 500)
+
+	schemaJSON, err := tolerant.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if !strings.Contains(schemaJSON, `"format":"email","x-format-mismatches":1`) {
+		t.Errorf("Expected email format with one mismatch, got %s", schemaJSON)
+	}
+
+	var schema Schema
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		t.Fatalf("Failed to unmarshal schema: %v", err)
+	}
+	if mismatches := schema.Properties["email"].Extensions["x-format-mismatches"]; mismatches != float64(1) {
+		t.Errorf("Expected x-format-mismatches to be read back, got %v", mismatches)
+	}
+}
+
+func TestFormatToleranceBelowThreshold(t *testing.T) {
+	generator := New(WithFormatTolerance(0.99))
+	for i := 0; i < 10; i++ {
+		generator.AddSample(fmt.Sprintf(`{"email": "user%d@example.com"}`, i))
+	}
+	generator.AddSample(`{"email": "n/a"}`)
+
+	// 10 out of 11 is below 99%.
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["email"].Format != "" || schema.Properties["email"].Extensions != nil {
+		t.Errorf("Expected no format below the threshold, got %+v", schema.Properties["email"])
+	}
+}
+
+func TestFormatToleranceNoMismatches(t *testing.T) {
+	generator := New(WithFormatTolerance(0.9))
+	generator.AddSample(`{"id": "550e8400-e29b-41d4-a716-446655440000"}`)
+
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["id"].Format != "uuid" {
+		t.Errorf("Expected uuid format, got %q", schema.Properties["id"].Format)
+	}
+	if schema.Properties["id"].Extensions != nil {
+		t.Errorf("Expected no mismatch annotation, got %v", schema.Properties["id"].Extensions)
+	}
+}
+
+func TestFormatToleranceBestMatch(t *testing.T) {
+	generator := New(
+		WithFormatTolerance(0.5),
+		WithPatternFormat("short-email", `^[a-z]@`),
+	)
+	generator.AddSample(`{"email": "a@example.com"}`)
+	generator.AddSample(`{"email": "bob@example.com"}`)
+	generator.AddSample(`{"email": "carol@example.com"}`)
+
+	// Both formats pass the threshold; the one matching more strings wins.
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["email"].Format != "email" {
+		t.Errorf("Expected email format, got %q", schema.Properties["email"].Format)
+	}
+}
+
+func TestFormatToleranceParallel(t *testing.T) {
+	sequential := New(WithFormatTolerance(0.99))
+	addEmails(t, sequential, 400, 7)
+	want, err := sequential.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	parallel := NewParallel(4, WithFormatTolerance(0.99))
+	addEmails(t, parallel, 400, 7)
+	got, err := parallel.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	if got != want {
+		t.Errorf("Parallel schema differs from sequential\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestFormatToleranceLoad(t *testing.T) {
+	generator := New(WithFormatTolerance(0.5))
+	err := generator.Load(`{"type": "object", "properties": {"email": {"type": "string", "format": "email", "x-format-mismatches": 3}}, "required": ["email"]}`)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	generator.AddSample(`{"email": "a@example.com"}`)
+	generator.AddSample(`{"email": "n/a"}`)
+
+	schema := generator.GetCurrentSchema()
+	if schema.Properties["email"].Format != "email" {
+		t.Errorf("Expected loaded format to tolerate a mismatch, got %q", schema.Properties["email"].Format)
+	}
+	if mismatches := schema.Properties["email"].Extensions["x-format-mismatches"]; mismatches != 1 {
+		t.Errorf("Expected one mismatch since Load, got %v", mismatches)
+	}
+}